@@ -2,34 +2,213 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/nockty/glox/internal/diagnostics"
 	"github.com/nockty/glox/internal/lox"
 )
 
+const usage = `Usage: glox [-vm] [-stress-gc] [script]
+       glox compile [-o output] script
+       glox disasm script
+       glox check [-format text|json|sarif] script`
+
+// exit codes, from sysexits.h
+const (
+	exitUsage     = 64
+	exitDataErr   = 65
+	exitNoInput   = 66
+	exitSoftware  = 70
+	exitCantCreat = 73
+)
+
+var (
+	useVM    bool
+	stressGC bool
+)
+
 func main() {
-	args := os.Args
-	if len(args) > 2 {
-		println("Usage: glox [script]")
-		os.Exit(64)
-	} else if len(args) == 2 {
-		runFile(args[1])
+	if len(os.Args) > 1 && os.Args[1] == "compile" {
+		compileMain(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		checkMain(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "disasm" {
+		if len(os.Args) != 3 {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(exitUsage)
+		}
+		disasm(os.Args[2])
+		return
+	}
+
+	flags := flag.NewFlagSet("glox", flag.ContinueOnError)
+	flags.BoolVar(&useVM, "vm", false, "run on the bytecode virtual machine instead of the tree-walk interpreter")
+	flags.BoolVar(&stressGC, "stress-gc", false, "collect garbage before every allocation of the virtual machine (for debugging)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(exitUsage)
+	}
+	args := flags.Args()
+	if len(args) > 1 {
+		flags.Usage()
+		os.Exit(exitUsage)
+	} else if len(args) == 1 {
+		runFile(args[0])
 	} else {
 		runPrompt()
 	}
 }
 
 func runFile(path string) {
+	source := readFile(path)
+	if lox.IsBytecode([]byte(source)) {
+		runBytecode(path, source)
+		return
+	}
+	if err := newSession()(path, source); err != nil {
+		exit(err)
+	}
+}
+
+// runBytecode runs a script compiled ahead of time with the compile command.
+func runBytecode(path, source string) {
+	function, err := lox.ReadBytecode(strings.NewReader(source))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		os.Exit(exitDataErr)
+	}
+	if err := lox.NewVM(vmOptions()...).Interpret(function); err != nil {
+		exit(err)
+	}
+}
+
+// compileMain compiles a script to a bytecode file that can be run directly.
+func compileMain(args []string) {
+	flags := flag.NewFlagSet("glox compile", flag.ContinueOnError)
+	output := flags.String("o", "", "output file (defaults to the script name with the .loxc extension)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		os.Exit(exitUsage)
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(exitUsage)
+	}
+	path := flags.Arg(0)
+	if *output == "" {
+		*output = strings.TrimSuffix(path, filepath.Ext(path)) + ".loxc"
+	}
+
+	source := readFile(path)
+	function, err := lox.Compile(path, source)
+	if err != nil {
+		lox.Report(os.Stderr, err, path, source)
+		exit(err)
+	}
+	var buf bytes.Buffer
+	if err := lox.WriteBytecode(&buf, function); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitSoftware)
+	}
+	if err := ioutil.WriteFile(*output, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitCantCreat)
+	}
+}
+
+// checkMain reports the static errors of a script without running it, and notes about what the virtual
+// machine doesn't support.
+func checkMain(args []string) {
+	flags := flag.NewFlagSet("glox check", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: text, json or sarif (json and sarif are written to stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		os.Exit(exitUsage)
+	}
+	if flags.NArg() != 1 || (*format != "text" && *format != "json" && *format != "sarif") {
+		flags.Usage()
+		os.Exit(exitUsage)
+	}
+	path := flags.Arg(0)
+	source := readFile(path)
+
+	found := lox.Check(path, source)
+	var err error
+	switch *format {
+	case "text":
+		renderer := diagnostics.NewRenderer(os.Stderr)
+		renderer.AddSource(path, source)
+		renderer.RenderAll(found)
+	case "json":
+		err = diagnostics.WriteJSON(os.Stdout, found)
+	case "sarif":
+		tool := diagnostics.Tool{Name: "glox", InformationURI: "https://github.com/nockty/glox"}
+		err = diagnostics.WriteSARIF(os.Stdout, tool, found, map[string]string{path: source})
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitSoftware)
+	}
+	// notes about the virtual machine don't prevent the script from running on the interpreter
+	for _, diagnostic := range found {
+		if diagnostic.Severity == diagnostics.Error {
+			os.Exit(exitDataErr)
+		}
+	}
+}
+
+// disasm compiles a script to bytecode and prints it instead of running it.
+func disasm(path string) {
+	source := readFile(path)
+	function, err := lox.Compile(path, source)
+	if err != nil {
+		lox.Report(os.Stderr, err, path, source)
+		exit(err)
+	}
+	lox.NewDisassembler(os.Stdout).Disassemble(function)
+}
+
+func readFile(path string) string {
 	bytes, err := ioutil.ReadFile(path)
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitNoInput)
+	}
+	return string(bytes)
+}
+
+// exit exits with the code matching the kind of an error, which has already been reported.
+func exit(err error) {
+	var list lox.ErrorList
+	if errors.As(err, &list) {
+		os.Exit(exitDataErr)
 	}
-	run(string(bytes))
+	os.Exit(exitSoftware)
 }
 
 func runPrompt() {
+	run := newSession()
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		print("> ")
@@ -40,21 +219,34 @@ func runPrompt() {
 		if err != nil {
 			panic(err)
 		}
-		run(line)
+		// errors don't end the session, they only abort the current line, and the session reports them
+		_ = run("", line)
 	}
 }
 
-func run(source string) {
-	// TODO when running files: exit 65 for static errors, exit 70 for runtime errors
-	scanner := lox.NewScanner(source)
-	tokens := scanner.ScanTokens()
-	if scanner.HadErrors() {
-		return
+// newSession returns a function that runs source code read from the named file, or from the REPL when file
+// is empty. Successive calls share the same global state, so that the REPL remembers the declarations of
+// previous lines. Errors are reported on stderr before being returned.
+func newSession() func(file, source string) error {
+	interpreter := lox.NewInterpreter(lox.WithOutput(os.Stdout), lox.WithErrorOutput(os.Stderr))
+	vm := lox.NewVM(vmOptions()...)
+	return func(file, source string) error {
+		if useVM {
+			function, err := lox.Compile(file, source)
+			if err != nil {
+				lox.Report(os.Stderr, err, file, source)
+				return err
+			}
+			return vm.Interpret(function)
+		}
+		return interpreter.Run(file, source)
 	}
-	parser := lox.NewParser(tokens)
-	statements := parser.Parse()
-	if parser.HadErrors() {
-		return
+}
+
+func vmOptions() []lox.VMOption {
+	options := []lox.VMOption{lox.WithVMOutput(os.Stdout), lox.WithVMErrorOutput(os.Stderr)}
+	if stressGC {
+		options = append(options, lox.WithStressGC())
 	}
-	lox.NewInterpreter().Interpret(statements)
+	return options
 }
@@ -0,0 +1,43 @@
+// Package diagnostics describes problems found in source code and renders them for humans, with an excerpt
+// of the offending source.
+package diagnostics
+
+// Severity tells how serious a diagnostic is.
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+	Note
+)
+
+var severityNames = [...]string{
+	Error:   "error",
+	Warning: "warning",
+	Note:    "note",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// MarshalText encodes a severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a problem found in source code.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	// Code identifies the kind of problem, for tools.
+	Code string `json:"code,omitempty"`
+	// Message is the primary message, describing the problem.
+	Message string `json:"message"`
+	// Span locates the problem. A span without a column only points to a line, and a span without a line
+	// points to nothing.
+	Span Span `json:"span"`
+	// Notes give additional context.
+	Notes []string `json:"notes,omitempty"`
+	// Help suggests how to fix the problem, if any.
+	Help string `json:"help,omitempty"`
+}
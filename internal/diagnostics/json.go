@@ -0,0 +1,16 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteJSON writes diagnostics as a JSON array. Spans locate positions with byte offsets and columns.
+func WriteJSON(w io.Writer, diagnostics []Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diagnostics)
+}
@@ -0,0 +1,42 @@
+package diagnostics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, WriteJSON(&output, nil))
+	assert.Equal(t, "[]\n", output.String())
+
+	output.Reset()
+	diagnostics := []Diagnostic{{
+		Severity: Warning,
+		Code:     "parse-error",
+		Message:  "Expect expression.",
+		Span:     Span{File: "test.lox", Line: 1, Column: 9, Offset: 8, Length: 1},
+		Help:     "help",
+	}}
+	require.NoError(t, WriteJSON(&output, diagnostics))
+	expected := `[
+  {
+    "severity": "warning",
+    "code": "parse-error",
+    "message": "Expect expression.",
+    "span": {
+      "file": "test.lox",
+      "line": 1,
+      "column": 9,
+      "offset": 8,
+      "length": 1
+    },
+    "help": "help"
+  }
+]
+`
+	assert.Equal(t, expected, output.String())
+}
@@ -0,0 +1,180 @@
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape sequences used to color the output
+const (
+	reset   = "\x1b[0m"
+	bold    = "\x1b[1m"
+	red     = "\x1b[31m"
+	yellow  = "\x1b[33m"
+	blue    = "\x1b[34m"
+	cyan    = "\x1b[36m"
+	noColor = ""
+)
+
+var severityColors = [...]string{
+	Error:   red,
+	Warning: yellow,
+	Note:    cyan,
+}
+
+// Renderer writes diagnostics the way modern compilers do:
+//
+//	error: Expect expression.
+//	 --> script.lox:1:10
+//	  |
+//	1 | print 1 +;
+//	  |          ^
+//	  = help: ...
+type Renderer struct {
+	w     io.Writer
+	color bool
+	// sources maps file names to their content. The content of files which are not known in advance is
+	// read when a diagnostic needs it.
+	sources map[string]string
+	// unreadable holds the files which could not be read, so that they are read only once.
+	unreadable map[string]bool
+}
+
+// NewRenderer creates a renderer writing to w. The output is colored when w is a terminal, unless the
+// NO_COLOR environment variable is set.
+func NewRenderer(w io.Writer) *Renderer {
+	color := false
+	if f, ok := w.(*os.File); ok {
+		color = IsTerminal(f) && os.Getenv("NO_COLOR") == ""
+	}
+	return &Renderer{
+		w:          w,
+		color:      color,
+		sources:    make(map[string]string),
+		unreadable: make(map[string]bool),
+	}
+}
+
+// SetColor enables or disables colored output.
+func (r *Renderer) SetColor(color bool) {
+	r.color = color
+}
+
+// AddSource registers the content of a file, so that diagnostics in it show an excerpt of the source. The
+// name of source code which doesn't come from a file is empty.
+func (r *Renderer) AddSource(file, source string) {
+	r.sources[file] = source
+}
+
+// Render writes a diagnostic.
+func (r *Renderer) Render(d Diagnostic) {
+	fmt.Fprintf(r.w, "%s%s:%s%s %s%s\n", r.style(bold+severityColors[d.Severity]), d.Severity, r.style(reset),
+		r.style(bold), d.Message, r.style(reset))
+	if d.Span.Line == 0 {
+		r.renderFooter(d, "")
+		return
+	}
+
+	line, ok := r.line(d.Span)
+	gutter := strings.Repeat(" ", len(strconv.Itoa(d.Span.Line)))
+	fmt.Fprintf(r.w, "%s%s-->%s %s\n", gutter, r.style(blue+bold), r.style(reset), d.Span)
+	if !ok {
+		r.renderFooter(d, gutter)
+		return
+	}
+	fmt.Fprintf(r.w, "%s %s|%s\n", gutter, r.style(blue+bold), r.style(reset))
+	fmt.Fprintf(r.w, "%s%d |%s %s\n", r.style(blue+bold), d.Span.Line, r.style(reset), line)
+	if d.Span.Column > 0 {
+		fmt.Fprintf(r.w, "%s %s|%s %s%s%s\n", gutter, r.style(blue+bold), r.style(reset),
+			r.style(bold+severityColors[d.Severity]), underline(line, d.Span), r.style(reset))
+	}
+	r.renderFooter(d, gutter)
+}
+
+// RenderAll writes diagnostics in order.
+func (r *Renderer) RenderAll(diagnostics []Diagnostic) {
+	for _, d := range diagnostics {
+		r.Render(d)
+	}
+}
+
+func (r *Renderer) renderFooter(d Diagnostic, gutter string) {
+	for _, note := range d.Notes {
+		fmt.Fprintf(r.w, "%s %s=%s note: %s\n", gutter, r.style(blue+bold), r.style(reset), note)
+	}
+	if d.Help != "" {
+		fmt.Fprintf(r.w, "%s %s=%s help: %s\n", gutter, r.style(blue+bold), r.style(reset), d.Help)
+	}
+}
+
+// style returns an escape sequence if the output is colored.
+func (r *Renderer) style(sequence string) string {
+	if !r.color {
+		return noColor
+	}
+	return sequence
+}
+
+// line returns the source line where span starts, without its line terminator.
+func (r *Renderer) line(span Span) (string, bool) {
+	source, ok := r.sources[span.File]
+	if !ok && span.File != "" && !r.unreadable[span.File] {
+		content, err := ioutil.ReadFile(span.File)
+		if err != nil {
+			r.unreadable[span.File] = true
+		} else {
+			source = string(content)
+			ok = true
+			r.sources[span.File] = source
+		}
+	}
+	if !ok {
+		return "", false
+	}
+	lines := strings.SplitN(source, "\n", span.Line+1)
+	if len(lines) < span.Line {
+		return "", false
+	}
+	return strings.TrimRight(lines[span.Line-1], "\r\n"), true
+}
+
+// underline returns carets under the part of line covered by span. A span going past the end of the line
+// is only underlined until the end of the line, and an empty span gets one caret.
+func underline(line string, span Span) string {
+	start := span.Column - 1
+	if start > len(line) {
+		start = len(line)
+	}
+	end := start + span.Length
+	if end > len(line) {
+		end = len(line)
+	}
+	var b strings.Builder
+	// keep the alignment of tabs and multi-byte characters
+	for _, c := range line[:start] {
+		if c == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	width := len([]rune(line[start:end]))
+	if width == 0 {
+		width = 1
+	}
+	b.WriteString(strings.Repeat("^", width))
+	return b.String()
+}
+
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,152 @@
+package diagnostics
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	source := "var a = 1;\n\tprint \"é\" + a +;\r\nvar s = \"two\nlines\";\n"
+	testCases := []struct {
+		name       string
+		diagnostic Diagnostic
+		expected   string
+	}{
+		{
+			name: "caret under the span",
+			diagnostic: Diagnostic{
+				Severity: Error,
+				Message:  "Expect ';' after value.",
+				Span:     Span{File: "test.lox", Line: 1, Column: 9, Offset: 8, Length: 1},
+			},
+			expected: `error: Expect ';' after value.
+ --> test.lox:1:9
+  |
+1 | var a = 1;
+  |         ^
+`,
+		},
+		{
+			name: "tabs and multi-byte characters before the span",
+			diagnostic: Diagnostic{
+				Severity: Warning,
+				Message:  "Expect expression.",
+				Span:     Span{File: "test.lox", Line: 2, Column: 11, Offset: 21, Length: 5},
+			},
+			expected: "warning: Expect expression.\n --> test.lox:2:11\n  |\n2 | \tprint \"é\" + a +;\n  | \t        ^^^^^\n",
+		},
+		{
+			name: "span over several lines",
+			diagnostic: Diagnostic{
+				Severity: Error,
+				Message:  "Unterminated string.",
+				Span:     Span{File: "test.lox", Line: 3, Column: 9, Offset: 39, Length: 12},
+				Help:     `add a closing '"'`,
+			},
+			expected: `error: Unterminated string.
+ --> test.lox:3:9
+  |
+3 | var s = "two
+  |         ^^^^
+  = help: add a closing '"'
+`,
+		},
+		{
+			name: "empty span at the end of a line",
+			diagnostic: Diagnostic{
+				Severity: Error,
+				Message:  "Expect expression.",
+				Span:     Span{File: "test.lox", Line: 1, Column: 11, Offset: 10},
+			},
+			expected: `error: Expect expression.
+ --> test.lox:1:11
+  |
+1 | var a = 1;
+  |           ^
+`,
+		},
+		{
+			name: "line only",
+			diagnostic: Diagnostic{
+				Severity: Error,
+				Message:  "Too many constants in one chunk.",
+				Span:     Span{File: "test.lox", Line: 1},
+				Notes:    []string{"first note", "second note"},
+			},
+			expected: `error: Too many constants in one chunk.
+ --> test.lox:1
+  |
+1 | var a = 1;
+  = note: first note
+  = note: second note
+`,
+		},
+		{
+			name: "unknown source",
+			diagnostic: Diagnostic{
+				Severity: Error,
+				Message:  "Expect expression.",
+				Span:     Span{Line: 12, Column: 3, Offset: 100, Length: 1},
+				Help:     "help",
+			},
+			expected: `error: Expect expression.
+  --> line 12:3
+   = help: help
+`,
+		},
+		{
+			name:       "no span",
+			diagnostic: Diagnostic{Severity: Note, Message: "Something happened.", Notes: []string{"note"}},
+			expected:   "note: Something happened.\n = note: note\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			renderer := NewRenderer(&output)
+			renderer.AddSource("test.lox", source)
+			renderer.Render(tc.diagnostic)
+			assert.Equal(t, tc.expected, output.String())
+		})
+	}
+}
+
+func TestRenderUnreadableFile(t *testing.T) {
+	var output bytes.Buffer
+	renderer := NewRenderer(&output)
+	file := filepath.Join(t.TempDir(), "missing.lox")
+	// the second diagnostic doesn't show an excerpt either once the file failed to be read
+	for i := 0; i < 2; i++ {
+		renderer.Render(Diagnostic{Severity: Error, Message: "Expect expression.", Span: Span{File: file, Line: 1, Column: 1}})
+	}
+	expected := fmt.Sprintf("error: Expect expression.\n --> %s:1:1\n", file)
+	assert.Equal(t, expected+expected, output.String())
+}
+
+func TestRenderColor(t *testing.T) {
+	var output bytes.Buffer
+	renderer := NewRenderer(&output)
+	renderer.AddSource("", "1 +;")
+	renderer.SetColor(true)
+	renderer.Render(Diagnostic{Severity: Error, Message: "Expect expression.", Span: Span{Line: 1, Column: 4, Offset: 3, Length: 1}})
+	expected := "\x1b[1m\x1b[31merror:\x1b[0m\x1b[1m Expect expression.\x1b[0m\n" +
+		" \x1b[34m\x1b[1m-->\x1b[0m line 1:4\n" +
+		"  \x1b[34m\x1b[1m|\x1b[0m\n" +
+		"\x1b[34m\x1b[1m1 |\x1b[0m 1 +;\n" +
+		"  \x1b[34m\x1b[1m|\x1b[0m \x1b[1m\x1b[31m   ^\x1b[0m\n"
+	assert.Equal(t, expected, output.String())
+}
+
+func TestSpan(t *testing.T) {
+	span := Span{File: "test.lox", Line: 2, Column: 3, Offset: 10, Length: 2}
+	assert.Equal(t, "test.lox:2:3", span.String())
+	assert.Equal(t, "line 2:3", Span{Line: 2, Column: 3}.String())
+	assert.Equal(t, "line 2", Span{Line: 2}.String())
+	assert.Equal(t, 12, span.End())
+	assert.Equal(t, Span{File: "test.lox", Line: 2, Column: 3, Offset: 10, Length: 10}, span.To(Span{Offset: 15, Length: 5}))
+	assert.Equal(t, span, span.To(Span{Offset: 10, Length: 1}))
+}
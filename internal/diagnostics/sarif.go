@@ -0,0 +1,170 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tool describes the program reporting diagnostics in a SARIF log.
+type Tool struct {
+	Name           string
+	Version        string
+	InformationURI string
+}
+
+// The subset of the SARIF 2.1.0 format used to report diagnostics.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	RuleIndex *int            `json:"ruleIndex,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// WriteSARIF writes diagnostics as a SARIF log, the format read by code scanning services. sources maps file
+// names to their content: SARIF columns count characters, so they are computed from the source when it is
+// available, and taken as bytes otherwise.
+func WriteSARIF(w io.Writer, tool Tool, diagnostics []Diagnostic, sources map[string]string) error {
+	driver := sarifDriver{
+		Name:           tool.Name,
+		Version:        tool.Version,
+		InformationURI: tool.InformationURI,
+		Rules:          []sarifRule{},
+	}
+	ruleIndexes := make(map[string]int)
+	results := make([]sarifResult, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		result := sarifResult{
+			RuleID:  d.Code,
+			Level:   d.Severity.String(),
+			Message: sarifMessage{Text: sarifText(d)},
+		}
+		if d.Code != "" {
+			index, ok := ruleIndexes[d.Code]
+			if !ok {
+				index = len(driver.Rules)
+				ruleIndexes[d.Code] = index
+				driver.Rules = append(driver.Rules, sarifRule{ID: d.Code})
+			}
+			result.RuleIndex = &index
+		}
+		if d.Span.Line > 0 {
+			result.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: d.Span.File},
+					Region:           sarifRegionOf(d.Span, sources),
+				},
+			}}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:       sarifTool{Driver: driver},
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifText returns the message of a diagnostic followed by its notes and help.
+func sarifText(d Diagnostic) string {
+	lines := []string{d.Message}
+	for _, note := range d.Notes {
+		lines = append(lines, "note: "+note)
+	}
+	if d.Help != "" {
+		lines = append(lines, "help: "+d.Help)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sarifRegionOf converts a span to a region, whose columns count characters and whose end is exclusive.
+func sarifRegionOf(span Span, sources map[string]string) sarifRegion {
+	if span.Column == 0 {
+		return sarifRegion{StartLine: span.Line}
+	}
+	source, ok := sources[span.File]
+	if !ok || span.End() > len(source) || span.Column > span.Offset+1 {
+		return sarifRegion{
+			StartLine:   span.Line,
+			StartColumn: span.Column,
+			EndLine:     span.Line,
+			EndColumn:   span.Column + span.Length,
+		}
+	}
+	lineStart := span.Offset - (span.Column - 1)
+	region := sarifRegion{
+		StartLine:   span.Line,
+		StartColumn: utf8.RuneCountInString(source[lineStart:span.Offset]) + 1,
+		EndLine:     span.Line,
+	}
+	// the span may go over several lines
+	text := source[span.Offset:span.End()]
+	if newLines := strings.Count(text, "\n"); newLines > 0 {
+		region.EndLine += newLines
+		lineStart = span.Offset + strings.LastIndex(text, "\n") + 1
+	}
+	region.EndColumn = utf8.RuneCountInString(source[lineStart:span.End()]) + 1
+	return region
+}
@@ -0,0 +1,74 @@
+package diagnostics
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	source := "print \"é\" + ;\nvar s = \"two\nlines\";\n"
+	diagnostics := []Diagnostic{
+		{
+			Severity: Error,
+			Code:     "parse-error",
+			Message:  "Expect expression.",
+			Span:     Span{File: "test.lox", Line: 1, Column: 14, Offset: 13, Length: 1},
+		},
+		{
+			Severity: Warning,
+			Code:     "scan-error",
+			Message:  "Unterminated string.",
+			Span:     Span{File: "test.lox", Line: 2, Column: 9, Offset: 23, Length: 11},
+			Notes:    []string{"a note"},
+			Help:     "some help",
+		},
+		{
+			Severity: Error,
+			Code:     "parse-error",
+			Message:  "Expect ';' after value.",
+			Span:     Span{File: "other.lox", Line: 3, Column: 2, Offset: 20, Length: 3},
+		},
+		{Severity: Note, Message: "Something happened."},
+	}
+	var output bytes.Buffer
+	tool := Tool{Name: "glox", Version: "1.0", InformationURI: "https://example.com"}
+	require.NoError(t, WriteSARIF(&output, tool, diagnostics, map[string]string{"test.lox": source}))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(output.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, sarifDriver{
+		Name:           "glox",
+		Version:        "1.0",
+		InformationURI: "https://example.com",
+		Rules:          []sarifRule{{ID: "parse-error"}, {ID: "scan-error"}},
+	}, run.Tool.Driver)
+	require.Len(t, run.Results, 4)
+
+	results := run.Results
+	assert.Equal(t, "error", results[0].Level)
+	assert.Equal(t, 0, *results[0].RuleIndex)
+	// columns count characters
+	assert.Equal(t, sarifRegion{StartLine: 1, StartColumn: 13, EndLine: 1, EndColumn: 14}, results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "test.lox", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	assert.Equal(t, "warning", results[1].Level)
+	assert.Equal(t, 1, *results[1].RuleIndex)
+	assert.Equal(t, "Unterminated string.\nnote: a note\nhelp: some help", results[1].Message.Text)
+	assert.Equal(t, sarifRegion{StartLine: 2, StartColumn: 9, EndLine: 3, EndColumn: 7}, results[1].Locations[0].PhysicalLocation.Region)
+
+	// without source, columns are bytes
+	assert.Equal(t, 0, *results[2].RuleIndex)
+	assert.Equal(t, sarifRegion{StartLine: 3, StartColumn: 2, EndLine: 3, EndColumn: 5}, results[2].Locations[0].PhysicalLocation.Region)
+
+	assert.Equal(t, "note", results[3].Level)
+	assert.Empty(t, results[3].RuleID)
+	assert.Nil(t, results[3].RuleIndex)
+	assert.Empty(t, results[3].Locations)
+}
@@ -0,0 +1,42 @@
+package diagnostics
+
+import "fmt"
+
+// Span locates a range of source code.
+type Span struct {
+	// File is the name of the source file, empty when the source doesn't come from a file (e.g. the REPL).
+	File string `json:"file,omitempty"`
+	// Line and Column locate the start of the span. They start at 1 and columns count bytes.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Offset is the byte offset of the start of the span in the source.
+	Offset int `json:"offset"`
+	// Length is the number of bytes in the span.
+	Length int `json:"length"`
+}
+
+// End returns the byte offset right after the span.
+func (s Span) End() int {
+	return s.Offset + s.Length
+}
+
+// To returns a span going from the start of s to the end of end.
+func (s Span) To(end Span) Span {
+	if end.End() > s.End() {
+		s.Length = end.End() - s.Offset
+	}
+	return s
+}
+
+// String returns the position of the start of the span, as file:line:col. The column is left out when it
+// is unknown.
+func (s Span) String() string {
+	position := fmt.Sprintf("%d", s.Line)
+	if s.Column > 0 {
+		position = fmt.Sprintf("%d:%d", s.Line, s.Column)
+	}
+	if s.File == "" {
+		return "line " + position
+	}
+	return s.File + ":" + position
+}
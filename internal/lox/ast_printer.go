@@ -23,6 +23,14 @@ func (a *AstPrinter) visitBinaryExpr(expr *BinaryExpr) string {
 	return a.parenthesize(expr.operator.Lexeme, expr.left, expr.right)
 }
 
+func (a *AstPrinter) visitCallExpr(expr *CallExpr) string {
+	return a.parenthesize("call", append([]Expr{expr.callee}, expr.arguments...)...)
+}
+
+func (a *AstPrinter) visitGetExpr(expr *GetExpr) string {
+	return a.parenthesize(fmt.Sprintf(". %s", expr.name.Lexeme), expr.object)
+}
+
 func (a *AstPrinter) visitGroupingExpr(expr *GroupingExpr) string {
 	return a.parenthesize("group", expr.expression)
 }
@@ -38,6 +46,49 @@ func (a *AstPrinter) visitLogicalExpr(expr *LogicalExpr) string {
 	return a.parenthesize(expr.operator.Lexeme, expr.left, expr.right)
 }
 
+func (a *AstPrinter) visitIndexExpr(expr *IndexExpr) string {
+	return a.parenthesize("[]", expr.object, expr.index)
+}
+
+func (a *AstPrinter) visitIndexSetExpr(expr *IndexSetExpr) string {
+	return a.parenthesize("[]=", expr.object, expr.index, expr.value)
+}
+
+func (a *AstPrinter) visitListExpr(expr *ListExpr) string {
+	return a.parenthesize("list", expr.elements...)
+}
+
+func (a *AstPrinter) visitMapExpr(expr *MapExpr) string {
+	entries := make([]Expr, 0, 2*len(expr.keys))
+	for idx, key := range expr.keys {
+		entries = append(entries, key, expr.values[idx])
+	}
+	return a.parenthesize("map", entries...)
+}
+
+func (a *AstPrinter) visitSliceExpr(expr *SliceExpr) string {
+	// the bounds of a slice are optional
+	bounds := []string{"_", "_"}
+	for idx, bound := range []Expr{expr.start, expr.end} {
+		if bound != nil {
+			bounds[idx] = a.Sprint(bound)
+		}
+	}
+	return fmt.Sprintf("([:] %s %s %s)", a.Sprint(expr.object), bounds[0], bounds[1])
+}
+
+func (a *AstPrinter) visitSetExpr(expr *SetExpr) string {
+	return a.parenthesize(fmt.Sprintf(". %s =", expr.name.Lexeme), expr.object, expr.value)
+}
+
+func (a *AstPrinter) visitSuperExpr(expr *SuperExpr) string {
+	return fmt.Sprintf("(super %s)", expr.method.Lexeme)
+}
+
+func (a *AstPrinter) visitThisExpr(expr *ThisExpr) string {
+	return "this"
+}
+
 func (a *AstPrinter) visitUnaryExpr(expr *UnaryExpr) string {
 	return a.parenthesize(expr.operator.Lexeme, expr.right)
 }
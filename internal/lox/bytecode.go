@@ -0,0 +1,590 @@
+package lox
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// Compiled scripts can be saved to a versioned binary format so that they run without being scanned,
+// parsed and compiled again. A bytecode file is laid out as follows, where integers are unsigned varints
+// unless stated otherwise and strings are a length followed by that many bytes:
+//
+// file       → magic version function ;
+//
+// magic      → 0x1b 'L' 'O' 'X' ;
+//
+// version    → uint16 (big endian) ;
+//
+// function   → name arity upvalues code lines constants ;
+//
+// code       → length byte* ;
+//
+// lines      → runCount ( line count )* ;
+//
+// constants  → count constant* ;
+//
+// constant   → 0x00 float64 (IEEE 754 bits, big endian) | 0x01 string | 0x02 function ;
+
+// bytecodeMagic starts every bytecode file. Its first byte can't start a lox script, so bytecode files
+// and source files can't be mistaken for each other.
+var bytecodeMagic = []byte{0x1b, 'L', 'O', 'X'}
+
+// bytecodeVersion changes whenever the format or the instruction set changes.
+const bytecodeVersion = 1
+
+// maxFunctionNesting bounds how deeply function prototypes can be nested in a bytecode file.
+const maxFunctionNesting = 256
+
+const (
+	constantNumber byte = iota
+	constantString
+	constantFunction
+)
+
+// ErrInvalidBytecode is wrapped by every error returned when reading a corrupt bytecode file.
+var ErrInvalidBytecode = errors.New("invalid bytecode")
+
+// IsBytecode reports whether data starts like a bytecode file.
+func IsBytecode(data []byte) bool {
+	return bytes.HasPrefix(data, bytecodeMagic)
+}
+
+// WriteBytecode writes the function of a compiled script in the bytecode file format.
+func WriteBytecode(w io.Writer, function *objFunction) error {
+	bw := bufio.NewWriter(w)
+	e := &bytecodeEncoder{w: bw}
+	e.writeBytes(bytecodeMagic)
+	version := make([]byte, 2)
+	binary.BigEndian.PutUint16(version, bytecodeVersion)
+	e.writeBytes(version)
+	e.writeFunction(function)
+	if e.err != nil {
+		return fmt.Errorf("write bytecode: %w", e.err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write bytecode: %w", err)
+	}
+	return nil
+}
+
+// ReadBytecode reads the function of a compiled script from a bytecode file. The bytecode is checked so
+// that a corrupt or truncated file results in an error rather than a crash of the virtual machine. Values of
+// the wrong type, which can't be checked before running, are runtime errors of the virtual machine.
+func ReadBytecode(r io.Reader) (*objFunction, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read bytecode: %w", err)
+	}
+	if !IsBytecode(data) {
+		return nil, fmt.Errorf("%w: not a bytecode file", ErrInvalidBytecode)
+	}
+	d := &bytecodeDecoder{data: data, offset: len(bytecodeMagic)}
+	version, err := d.readUint16()
+	if err != nil {
+		return nil, fmt.Errorf("%w: version: %v", ErrInvalidBytecode, err)
+	}
+	if version != bytecodeVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d, expected %d", ErrInvalidBytecode, version, bytecodeVersion)
+	}
+	function, err := d.readFunction(0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBytecode, err)
+	}
+	if function.arity != 0 || function.upvalueCount != 0 {
+		return nil, fmt.Errorf("%w: the script can't have parameters or upvalues", ErrInvalidBytecode)
+	}
+	if d.offset != len(d.data) {
+		return nil, fmt.Errorf("%w: %d unexpected bytes after the script", ErrInvalidBytecode, len(d.data)-d.offset)
+	}
+	return function, nil
+}
+
+type bytecodeEncoder struct {
+	w *bufio.Writer
+	// err is the first write error. Once it is set, nothing else is written.
+	err error
+}
+
+func (e *bytecodeEncoder) writeBytes(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+func (e *bytecodeEncoder) writeUvarint(n int) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	e.writeBytes(buf[:binary.PutUvarint(buf, uint64(n))])
+}
+
+func (e *bytecodeEncoder) writeString(s string) {
+	e.writeUvarint(len(s))
+	e.writeBytes([]byte(s))
+}
+
+func (e *bytecodeEncoder) writeFunction(function *objFunction) {
+	e.writeString(function.name)
+	e.writeUvarint(function.arity)
+	e.writeUvarint(function.upvalueCount)
+	c := function.chunk
+	e.writeUvarint(len(c.code))
+	e.writeBytes(c.code)
+	e.writeLines(c.lines)
+	e.writeUvarint(len(c.constants))
+	for _, constant := range c.constants {
+		e.writeConstant(constant)
+	}
+}
+
+// writeLines writes the line table run-length encoded, since consecutive bytes mostly share a line.
+func (e *bytecodeEncoder) writeLines(lines []int) {
+	runs := make([][2]int, 0)
+	for _, line := range lines {
+		if len(runs) > 0 && runs[len(runs)-1][0] == line {
+			runs[len(runs)-1][1]++
+			continue
+		}
+		runs = append(runs, [2]int{line, 1})
+	}
+	e.writeUvarint(len(runs))
+	for _, run := range runs {
+		e.writeUvarint(run[0])
+		e.writeUvarint(run[1])
+	}
+}
+
+func (e *bytecodeEncoder) writeConstant(constant value) {
+	if constant.typ == valueNumber {
+		e.writeBytes([]byte{constantNumber})
+		bits := make([]byte, 8)
+		binary.BigEndian.PutUint64(bits, math.Float64bits(constant.number))
+		e.writeBytes(bits)
+		return
+	}
+	switch o := constant.obj.(type) {
+	case *objString:
+		e.writeBytes([]byte{constantString})
+		e.writeString(o.chars)
+	case *objFunction:
+		e.writeBytes([]byte{constantFunction})
+		e.writeFunction(o)
+	default:
+		if e.err == nil {
+			e.err = fmt.Errorf("unsupported constant %v", constant)
+		}
+	}
+}
+
+type bytecodeDecoder struct {
+	data   []byte
+	offset int
+}
+
+var errTruncated = errors.New("unexpected end of file")
+
+func (d *bytecodeDecoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.offset {
+		return nil, errTruncated
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *bytecodeDecoder) readByte() (byte, error) {
+	b, err := d.readBytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *bytecodeDecoder) readUint16() (uint16, error) {
+	b, err := d.readBytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b), nil
+}
+
+func (d *bytecodeDecoder) readUvarint() (int, error) {
+	n, read := binary.Uvarint(d.data[d.offset:])
+	if read == 0 {
+		return 0, errTruncated
+	}
+	if read < 0 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("integer out of range at offset %d", d.offset)
+	}
+	d.offset += read
+	return int(n), nil
+}
+
+// readCount reads a length or a number of items. Since each byte or item takes at least one byte, it
+// can't exceed the number of bytes left in the file, which keeps corrupt files from causing huge
+// allocations.
+func (d *bytecodeDecoder) readCount() (int, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > len(d.data)-d.offset {
+		return 0, errTruncated
+	}
+	return n, nil
+}
+
+func (d *bytecodeDecoder) readString() (string, error) {
+	n, err := d.readCount()
+	if err != nil {
+		return "", err
+	}
+	b, err := d.readBytes(n)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (d *bytecodeDecoder) readFunction(depth int) (*objFunction, error) {
+	if depth > maxFunctionNesting {
+		return nil, errors.New("functions nested too deeply")
+	}
+	name, err := d.readString()
+	if err != nil {
+		return nil, fmt.Errorf("function name: %w", err)
+	}
+	function := newObjFunction(name)
+	if function.arity, err = d.readUvarint(); err != nil {
+		return nil, fmt.Errorf("function %s: arity: %w", function, err)
+	}
+	if function.arity > maxArguments {
+		return nil, fmt.Errorf("function %s: arity %d is above %d", function, function.arity, maxArguments)
+	}
+	if function.upvalueCount, err = d.readUvarint(); err != nil {
+		return nil, fmt.Errorf("function %s: upvalue count: %w", function, err)
+	}
+	if function.upvalueCount > maxLocals {
+		return nil, fmt.Errorf("function %s: upvalue count %d is above %d", function, function.upvalueCount, maxLocals)
+	}
+
+	c := function.chunk
+	codeLength, err := d.readCount()
+	if err != nil {
+		return nil, fmt.Errorf("function %s: code: %w", function, err)
+	}
+	code, err := d.readBytes(codeLength)
+	if err != nil {
+		return nil, fmt.Errorf("function %s: code: %w", function, err)
+	}
+	c.code = append(c.code, code...)
+	if c.lines, err = d.readLines(len(c.code)); err != nil {
+		return nil, fmt.Errorf("function %s: line table: %w", function, err)
+	}
+
+	constantCount, err := d.readCount()
+	if err != nil {
+		return nil, fmt.Errorf("function %s: constants: %w", function, err)
+	}
+	for i := 0; i < constantCount; i++ {
+		constant, err := d.readConstant(depth)
+		if err != nil {
+			return nil, fmt.Errorf("function %s: constant %d: %w", function, i, err)
+		}
+		c.addConstant(constant)
+	}
+
+	if err := verifyChunk(function); err != nil {
+		return nil, fmt.Errorf("function %s: %w", function, err)
+	}
+	return function, nil
+}
+
+func (d *bytecodeDecoder) readLines(codeLength int) ([]int, error) {
+	runCount, err := d.readCount()
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]int, 0, codeLength)
+	for i := 0; i < runCount; i++ {
+		line, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		count, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if count > codeLength-len(lines) {
+			return nil, fmt.Errorf("more lines than the %d bytes of code", codeLength)
+		}
+		for j := 0; j < count; j++ {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != codeLength {
+		return nil, fmt.Errorf("%d lines for %d bytes of code", len(lines), codeLength)
+	}
+	return lines, nil
+}
+
+func (d *bytecodeDecoder) readConstant(depth int) (value, error) {
+	tag, err := d.readByte()
+	if err != nil {
+		return nilValue, err
+	}
+	switch tag {
+	case constantNumber:
+		bits, err := d.readBytes(8)
+		if err != nil {
+			return nilValue, err
+		}
+		return numberValue(math.Float64frombits(binary.BigEndian.Uint64(bits))), nil
+	case constantString:
+		s, err := d.readString()
+		if err != nil {
+			return nilValue, err
+		}
+		return objValue(&objString{chars: s}), nil
+	case constantFunction:
+		function, err := d.readFunction(depth + 1)
+		if err != nil {
+			return nilValue, err
+		}
+		return objValue(function), nil
+	}
+	return nilValue, fmt.Errorf("unknown constant tag %d", tag)
+}
+
+// verifyChunk checks that the instructions of a function decode properly: every opcode exists, operands
+// are within the code, constants have the type the instruction expects and jumps land on instructions. It
+// then checks how they use the stack with verifyStack.
+func verifyChunk(function *objFunction) error {
+	c := function.chunk
+	if len(c.code) == 0 || opCode(c.code[len(c.code)-1]) != opReturn {
+		return errors.New("code does not end with OP_RETURN")
+	}
+	instructions := make(map[int]bool)
+	jumps := make(map[int]int)
+	for offset := 0; offset < len(c.code); {
+		instructions[offset] = true
+		op := opCode(c.code[offset])
+		if int(op) >= len(opCodeNames) {
+			return fmt.Errorf("unknown opcode %d at offset %d", op, offset)
+		}
+		length := instructionLength(op)
+		if offset+length > len(c.code) {
+			return fmt.Errorf("%s at offset %d: %w", op, offset, errTruncated)
+		}
+		switch op {
+		case opConstant:
+			if err := verifyConstant(c, offset, constantNumber, constantString); err != nil {
+				return err
+			}
+		case opGetGlobal, opDefineGlobal, opSetGlobal, opGetProperty, opSetProperty, opGetSuper, opClass,
+			opMethod, opInvoke, opSuperInvoke:
+			if err := verifyConstant(c, offset, constantString); err != nil {
+				return err
+			}
+		case opGetUpvalue, opSetUpvalue:
+			if int(c.code[offset+1]) >= function.upvalueCount {
+				return fmt.Errorf("%s at offset %d: upvalue %d out of range", op, offset, c.code[offset+1])
+			}
+		case opJump, opJumpIfFalse:
+			jumps[offset] = offset + length + readShort(c, offset+1)
+		case opLoop:
+			jumps[offset] = offset + length - readShort(c, offset+1)
+		case opClosure:
+			if err := verifyConstant(c, offset, constantFunction); err != nil {
+				return err
+			}
+			nested := c.constants[readShort(c, offset+1)].obj.(*objFunction)
+			length += 2 * nested.upvalueCount
+			if offset+length > len(c.code) {
+				return fmt.Errorf("%s at offset %d: %w", op, offset, errTruncated)
+			}
+			for i := offset + 3; i < offset+length; i += 2 {
+				if c.code[i] > 1 {
+					return fmt.Errorf("%s at offset %d: invalid upvalue kind %d", op, offset, c.code[i])
+				}
+				if c.code[i] == 0 && int(c.code[i+1]) >= function.upvalueCount {
+					return fmt.Errorf("%s at offset %d: upvalue %d out of range", op, offset, c.code[i+1])
+				}
+			}
+		}
+		offset += length
+	}
+	for offset, target := range jumps {
+		if !instructions[target] {
+			return fmt.Errorf("%s at offset %d: jump target %d is not an instruction", opCode(c.code[offset]), offset, target)
+		}
+	}
+	return verifyStack(function)
+}
+
+// stackState is what the verifier knows about the stack of a function before an instruction: the number of
+// values in the slots of the function, the function itself included, and the slots closures may have captured.
+type stackState struct {
+	depth    int
+	captured [maxLocals]bool
+}
+
+// verifyStack checks how the instructions of a function, which verifyChunk decoded, use the stack along every
+// path through the code: instructions find the values they take on the stack, locals are within the stack,
+// only OP_CLOSE_UPVALUE pops a captured slot, paths joining have the same stack depth and no path runs past the
+// end of the code.
+func verifyStack(function *objFunction) error {
+	c := function.chunk
+	states := map[int]*stackState{0: {depth: function.arity + 1}}
+	pending := []int{0}
+	for len(pending) > 0 {
+		offset := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		state := *states[offset]
+		op := opCode(c.code[offset])
+		length := instructionLength(op)
+		// an instruction takes the values it needs from the top of the stack and changes the depth by effect
+		needs, effect := 0, 0
+		next := []int{offset + length}
+		switch op {
+		case opConstant, opNil, opTrue, opFalse, opGetGlobal, opGetUpvalue, opClass:
+			effect = 1
+		case opPop, opDefineGlobal, opPrint:
+			needs, effect = 1, -1
+		case opGetLocal, opSetLocal:
+			if slot := int(c.code[offset+1]); slot >= state.depth {
+				return fmt.Errorf("%s at offset %d: local %d out of range", op, offset, slot)
+			}
+			if op == opGetLocal {
+				effect = 1
+			} else {
+				needs = 1
+			}
+		case opSetGlobal, opSetUpvalue, opGetProperty, opNot, opNegate:
+			needs = 1
+		case opSetProperty, opGetSuper, opEqual, opNotEqual, opGreater, opGreaterEqual, opLess, opLessEqual,
+			opAdd, opSubtract, opMultiply, opDivide, opInherit, opMethod:
+			needs, effect = 2, -1
+		case opJump, opLoop:
+			next = []int{offset + length + readShort(c, offset+1)}
+			if op == opLoop {
+				next = []int{offset + length - readShort(c, offset+1)}
+			}
+		case opJumpIfFalse:
+			needs = 1
+			next = append(next, offset+length+readShort(c, offset+1))
+		case opCall, opInvoke:
+			argCount := int(c.code[offset+length-1])
+			needs, effect = argCount+1, -argCount
+		case opSuperInvoke:
+			argCount := int(c.code[offset+length-1])
+			needs, effect = argCount+2, -argCount-1
+		case opClosure:
+			nested := c.constants[readShort(c, offset+1)].obj.(*objFunction)
+			length += 2 * nested.upvalueCount
+			for i := offset + 3; i < offset+length; i += 2 {
+				if c.code[i] == 1 {
+					if int(c.code[i+1]) >= state.depth {
+						return fmt.Errorf("%s at offset %d: local %d out of range", op, offset, c.code[i+1])
+					}
+					state.captured[c.code[i+1]] = true
+				}
+			}
+			effect = 1
+			next = []int{offset + length}
+		case opCloseUpvalue:
+			needs, effect = 1, -1
+			if state.depth > 0 && state.depth-1 < maxLocals {
+				state.captured[state.depth-1] = false
+			}
+		case opReturn:
+			// the slots below the result are closed rather than popped
+			needs, effect = 1, -1
+			next = nil
+		}
+		if needs > state.depth {
+			return fmt.Errorf("%s at offset %d: needs %d values, the stack has %d", op, offset, needs, state.depth)
+		}
+		for slot := state.depth + effect; slot < state.depth && slot < maxLocals; slot++ {
+			if state.captured[slot] {
+				return fmt.Errorf("%s at offset %d: pops the captured local %d", op, offset, slot)
+			}
+		}
+		state.depth += effect
+
+		for _, target := range next {
+			if target >= len(c.code) {
+				return fmt.Errorf("%s at offset %d: runs past the end of the code", op, offset)
+			}
+			known, ok := states[target]
+			if !ok {
+				copied := state
+				states[target] = &copied
+				pending = append(pending, target)
+				continue
+			}
+			if known.depth != state.depth {
+				return fmt.Errorf("%s at offset %d: stack depth %d at offset %d, expected %d", op, offset, state.depth, target, known.depth)
+			}
+			// a slot captured along one of the paths joining is considered captured
+			changed := false
+			for slot, captured := range state.captured {
+				if captured && !known.captured[slot] {
+					known.captured[slot] = true
+					changed = true
+				}
+			}
+			if changed {
+				pending = append(pending, target)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyConstant checks that the constant operand of the instruction at offset exists and is of one of
+// the given kinds.
+func verifyConstant(c *chunk, offset int, kinds ...byte) error {
+	op := opCode(c.code[offset])
+	index := readShort(c, offset+1)
+	if index >= len(c.constants) {
+		return fmt.Errorf("%s at offset %d: constant %d out of range", op, offset, index)
+	}
+	kind := constantKind(c.constants[index])
+	for _, expected := range kinds {
+		if kind == expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s at offset %d: constant %d has the wrong type", op, offset, index)
+}
+
+func constantKind(constant value) byte {
+	if constant.typ == valueNumber {
+		return constantNumber
+	}
+	if _, ok := constant.obj.(*objFunction); ok {
+		return constantFunction
+	}
+	return constantString
+}
+
+// instructionLength returns the size in bytes of an instruction and its operands. For opClosure, it does
+// not include the upvalue operands, whose number depends on the function.
+func instructionLength(op opCode) int {
+	switch op {
+	case opGetLocal, opSetLocal, opGetUpvalue, opSetUpvalue, opCall:
+		return 2
+	case opConstant, opGetGlobal, opDefineGlobal, opSetGlobal, opGetProperty, opSetProperty, opGetSuper,
+		opJump, opJumpIfFalse, opLoop, opClosure, opClass, opMethod:
+		return 3
+	case opInvoke, opSuperInvoke:
+		return 4
+	}
+	return 1
+}
@@ -0,0 +1,152 @@
+package lox
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bytecodeTestSource = `
+class A {
+  init(name) {
+    this.name = name;
+  }
+  greet() {
+    return "hello " + this.name;
+  }
+}
+class B < A {
+  greet() {
+    return super.greet() + "!";
+  }
+}
+fun makeCounter() {
+  var i = 0;
+  fun count() {
+    i = i + 1;
+    return i;
+  }
+  return count;
+}
+var counter = makeCounter();
+counter();
+print counter();
+print B("bytecode").greet();
+print 1.5 * 2;`
+
+func compileBytecode(t *testing.T, source string) []byte {
+	t.Helper()
+	statements, _ := resolve(t, source)
+	compiler := NewCompiler()
+	function := compiler.Compile(statements)
+	require.False(t, compiler.HadErrors())
+	var buf bytes.Buffer
+	require.NoError(t, WriteBytecode(&buf, function))
+	return buf.Bytes()
+}
+
+func TestBytecodeRoundTrip(t *testing.T) {
+	data := compileBytecode(t, bytecodeTestSource)
+	assert.True(t, IsBytecode(data))
+	assert.False(t, IsBytecode([]byte(bytecodeTestSource)))
+
+	function, err := ReadBytecode(bytes.NewReader(data))
+	require.NoError(t, err)
+	var output bytes.Buffer
+	require.NoError(t, NewVM(WithVMOutput(&output)).Interpret(function))
+	assert.Equal(t, "2\nhello bytecode!\n3\n", output.String())
+
+	// line numbers survive the round trip
+	var expected, actual bytes.Buffer
+	statements, _ := resolve(t, bytecodeTestSource)
+	NewDisassembler(&expected).Disassemble(NewCompiler().Compile(statements))
+	NewDisassembler(&actual).Disassemble(function)
+	assert.Equal(t, expected.String(), actual.String())
+}
+
+func TestReadBytecodeTruncated(t *testing.T) {
+	data := compileBytecode(t, bytecodeTestSource)
+	for length := 0; length < len(data); length++ {
+		_, err := ReadBytecode(bytes.NewReader(data[:length]))
+		require.Error(t, err, "length %d", length)
+		assert.True(t, errors.Is(err, ErrInvalidBytecode), "length %d", length)
+	}
+}
+
+func TestReadBytecodeCorruptByte(t *testing.T) {
+	data := compileBytecode(t, bytecodeTestSource)
+	// every opcode and small operand, and bytes changing the length of varints
+	values := []byte{0x7f, 0x80, 0xff}
+	for b := range opCodeNames {
+		// OP_LOOP is left out since a corrupt file may well loop forever, which isn't a crash
+		if opCode(b) != opLoop {
+			values = append(values, byte(b))
+		}
+	}
+	corrupt := make([]byte, len(data))
+	for offset := range data {
+		for _, b := range values {
+			copy(corrupt, data)
+			corrupt[offset] = b
+			// a corrupt file is either rejected or runs into a runtime error at worst
+			require.NotPanics(t, func() {
+				function, err := ReadBytecode(bytes.NewReader(corrupt))
+				if err == nil {
+					_ = NewVM(WithVMOutput(ioutil.Discard)).Interpret(function)
+				}
+			}, "byte %d at offset %d", b, offset)
+		}
+	}
+}
+
+func TestReadBytecodeCorrupt(t *testing.T) {
+	// print 1;
+	valid := []byte{
+		0x1b, 'L', 'O', 'X', 0, 1,
+		// name, arity, upvalues
+		0, 0, 0,
+		// code
+		7, byte(opConstant), 0, 0, byte(opPrint), byte(opNil), byte(opReturn), byte(opReturn),
+		// lines
+		1, 1, 7,
+		// constants
+		1, constantNumber, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0,
+	}
+	_, err := ReadBytecode(bytes.NewReader(valid))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		offset   int
+		value    byte
+		expected string
+	}{
+		{name: "magic", offset: 1, value: 'l', expected: "invalid bytecode: not a bytecode file"},
+		{name: "version", offset: 5, value: 9, expected: "invalid bytecode: unsupported version 9, expected 1"},
+		{name: "opcode", offset: 13, value: 0xff, expected: "unknown opcode 255 at offset 3"},
+		{name: "constant index", offset: 12, value: 1, expected: "OP_CONSTANT at offset 0: constant 1 out of range"},
+		{name: "line table", offset: 19, value: 6, expected: "line table: 6 lines for 7 bytes of code"},
+		{name: "constant tag", offset: 21, value: 7, expected: "constant 0: unknown constant tag 7"},
+		{name: "last instruction", offset: 16, value: byte(opNil), expected: "code does not end with OP_RETURN"},
+		{name: "local", offset: 14, value: byte(opGetLocal), expected: "OP_GET_LOCAL at offset 4: local 36 out of range"},
+		{name: "stack depth", offset: 14, value: byte(opPrint), expected: "OP_RETURN at offset 5: needs 1 values, the stack has 0"},
+		{name: "arity", offset: 7, value: 1, expected: "invalid bytecode: the script can't have parameters or upvalues"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := append([]byte{}, valid...)
+			data[tc.offset] = tc.value
+			_, err := ReadBytecode(bytes.NewReader(data))
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidBytecode))
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+
+	_, err = ReadBytecode(bytes.NewReader(append(valid, 0)))
+	assert.EqualError(t, err, "invalid bytecode: 1 unexpected bytes after the script")
+}
@@ -0,0 +1,137 @@
+package lox
+
+import "fmt"
+
+type opCode byte
+
+const (
+	// opConstant pushes the constant at the 2-byte index operand.
+	opConstant opCode = iota
+	opNil
+	opTrue
+	opFalse
+	opPop
+	// opGetLocal and opSetLocal take the 1-byte stack slot of the local, relative to the frame.
+	opGetLocal
+	opSetLocal
+	// global opcodes take the 2-byte index of the constant holding the variable name.
+	opGetGlobal
+	opDefineGlobal
+	opSetGlobal
+	// opGetUpvalue and opSetUpvalue take the 1-byte index of the upvalue in the current closure.
+	opGetUpvalue
+	opSetUpvalue
+	// property opcodes take the 2-byte index of the constant holding the property name.
+	opGetProperty
+	opSetProperty
+	opGetSuper
+	opEqual
+	opNotEqual
+	opGreater
+	opGreaterEqual
+	opLess
+	opLessEqual
+	opAdd
+	opSubtract
+	opMultiply
+	opDivide
+	opNot
+	opNegate
+	opPrint
+	// jump opcodes take a 2-byte offset. opLoop jumps backwards.
+	opJump
+	opJumpIfFalse
+	opLoop
+	// opCall takes the 1-byte argument count.
+	opCall
+	// opInvoke and opSuperInvoke take the 2-byte index of the method name constant and the 1-byte
+	// argument count.
+	opInvoke
+	opSuperInvoke
+	// opClosure takes the 2-byte index of the function constant followed by a pair of bytes (isLocal,
+	// index) for each upvalue the function captures.
+	opClosure
+	opCloseUpvalue
+	opReturn
+	// opClass and opMethod take the 2-byte index of the constant holding the class or method name.
+	opClass
+	opInherit
+	opMethod
+)
+
+var opCodeNames = [...]string{
+	opConstant:     "OP_CONSTANT",
+	opNil:          "OP_NIL",
+	opTrue:         "OP_TRUE",
+	opFalse:        "OP_FALSE",
+	opPop:          "OP_POP",
+	opGetLocal:     "OP_GET_LOCAL",
+	opSetLocal:     "OP_SET_LOCAL",
+	opGetGlobal:    "OP_GET_GLOBAL",
+	opDefineGlobal: "OP_DEFINE_GLOBAL",
+	opSetGlobal:    "OP_SET_GLOBAL",
+	opGetUpvalue:   "OP_GET_UPVALUE",
+	opSetUpvalue:   "OP_SET_UPVALUE",
+	opGetProperty:  "OP_GET_PROPERTY",
+	opSetProperty:  "OP_SET_PROPERTY",
+	opGetSuper:     "OP_GET_SUPER",
+	opEqual:        "OP_EQUAL",
+	opNotEqual:     "OP_NOT_EQUAL",
+	opGreater:      "OP_GREATER",
+	opGreaterEqual: "OP_GREATER_EQUAL",
+	opLess:         "OP_LESS",
+	opLessEqual:    "OP_LESS_EQUAL",
+	opAdd:          "OP_ADD",
+	opSubtract:     "OP_SUBTRACT",
+	opMultiply:     "OP_MULTIPLY",
+	opDivide:       "OP_DIVIDE",
+	opNot:          "OP_NOT",
+	opNegate:       "OP_NEGATE",
+	opPrint:        "OP_PRINT",
+	opJump:         "OP_JUMP",
+	opJumpIfFalse:  "OP_JUMP_IF_FALSE",
+	opLoop:         "OP_LOOP",
+	opCall:         "OP_CALL",
+	opInvoke:       "OP_INVOKE",
+	opSuperInvoke:  "OP_SUPER_INVOKE",
+	opClosure:      "OP_CLOSURE",
+	opCloseUpvalue: "OP_CLOSE_UPVALUE",
+	opReturn:       "OP_RETURN",
+	opClass:        "OP_CLASS",
+	opInherit:      "OP_INHERIT",
+	opMethod:       "OP_METHOD",
+}
+
+func (op opCode) String() string {
+	if int(op) < len(opCodeNames) {
+		return opCodeNames[op]
+	}
+	return fmt.Sprintf("OP_UNKNOWN(%d)", byte(op))
+}
+
+// chunk is a sequence of bytecode instructions along with the data needed to run them.
+type chunk struct {
+	code []byte
+	// lines holds the source line of each byte in code.
+	lines     []int
+	constants []value
+}
+
+func newChunk() *chunk {
+	return &chunk{
+		code:      make([]byte, 0),
+		lines:     make([]int, 0),
+		constants: make([]value, 0),
+	}
+}
+
+func (c *chunk) write(b byte, line int) {
+	c.code = append(c.code, b)
+	c.lines = append(c.lines, line)
+}
+
+// addConstant adds a value to the constant pool and returns its index.
+func (c *chunk) addConstant(v value) int {
+	c.constants = append(c.constants, v)
+	return len(c.constants) - 1
+}
@@ -0,0 +1,117 @@
+package lox
+
+import "fmt"
+
+type loxClass struct {
+	name       string
+	superclass *loxClass
+	methods    map[string]*loxFunction
+}
+
+// loxClass implements LoxCallable
+var _ LoxCallable = &loxClass{}
+
+func newLoxClass(name string, superclass *loxClass, methods map[string]*loxFunction) *loxClass {
+	return &loxClass{
+		name:       name,
+		superclass: superclass,
+		methods:    methods,
+	}
+}
+
+// findMethod looks up a method in the class, then in its superclass chain.
+func (c *loxClass) findMethod(name string) (*loxFunction, bool) {
+	if method, ok := c.methods[name]; ok {
+		return method, true
+	}
+	if c.superclass != nil {
+		return c.superclass.findMethod(name)
+	}
+	return nil, false
+}
+
+// isSubclassOf reports whether the class is other or inherits from it.
+func (c *loxClass) isSubclassOf(other *loxClass) bool {
+	for class := c; class != nil; class = class.superclass {
+		if class == other {
+			return true
+		}
+	}
+	return false
+}
+
+// arity is the arity of the initializer, if any.
+func (c *loxClass) arity() int {
+	initializer, ok := c.findMethod("init")
+	if !ok {
+		return 0
+	}
+	return initializer.arity()
+}
+
+// call creates a new instance of the class and runs its initializer.
+func (c *loxClass) call(i *interpreter, paren Token, arguments []interface{}) interface{} {
+	if err := i.allocate(paren, instanceSize); err != nil {
+		return err
+	}
+	instance := newLoxInstance(c)
+	initializer, ok := c.findMethod("init")
+	if ok {
+		err, ok := initializer.bind(instance).call(i, paren, arguments).(*RuntimeError)
+		if ok {
+			return err
+		}
+	}
+	return instance
+}
+
+func (c *loxClass) String() string {
+	return c.name
+}
+
+type loxInstance struct {
+	class  *loxClass
+	fields map[string]interface{}
+}
+
+func newLoxInstance(class *loxClass) *loxInstance {
+	return &loxInstance{
+		class:  class,
+		fields: make(map[string]interface{}),
+	}
+}
+
+// get looks up a property of the instance. Fields shadow methods.
+func (i *loxInstance) get(name Token) (interface{}, *RuntimeError) {
+	if value, ok := i.fields[name.Lexeme]; ok {
+		return value, nil
+	}
+	if method, ok := i.class.findMethod(name.Lexeme); ok {
+		return method.bind(i), nil
+	}
+	return nil, &RuntimeError{
+		Token:   name,
+		Message: fmt.Sprintf("Undefined property '%s'.", name.Lexeme),
+	}
+}
+
+// method returns the property of the instance called name if it can be called, either a field holding a
+// function or a bound method.
+func (i *loxInstance) method(name string) (LoxCallable, bool) {
+	if value, ok := i.fields[name]; ok {
+		callable, ok := value.(LoxCallable)
+		return callable, ok
+	}
+	if method, ok := i.class.findMethod(name); ok {
+		return method.bind(i), true
+	}
+	return nil, false
+}
+
+func (i *loxInstance) set(name Token, value interface{}) {
+	i.fields[name.Lexeme] = value
+}
+
+func (i *loxInstance) String() string {
+	return fmt.Sprintf("%s instance", i.class.name)
+}
@@ -0,0 +1,397 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// loxList is a list of values. Lists are mutable, so two lists are equal only if they are the same list.
+type loxList struct {
+	elements []interface{}
+}
+
+func newLoxList(elements []interface{}) *loxList {
+	return &loxList{elements: elements}
+}
+
+func (l *loxList) String() string {
+	var b strings.Builder
+	b.WriteString("[")
+	for idx, element := range l.elements {
+		if idx > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(stringifyElement(element))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// loxMap maps keys to values and iterates in insertion order. Keys are compared like with isEqual: maps are
+// mutable, so two maps are equal only if they are the same map.
+type loxMap struct {
+	entries map[interface{}]interface{}
+	keys    []interface{}
+}
+
+func newLoxMap() *loxMap {
+	return &loxMap{entries: make(map[interface{}]interface{})}
+}
+
+func (m *loxMap) get(key interface{}) (interface{}, bool) {
+	value, ok := m.entries[key]
+	return value, ok
+}
+
+func (m *loxMap) set(key, value interface{}) {
+	if _, ok := m.entries[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.entries[key] = value
+}
+
+// delete removes key from the map and reports whether it was there.
+func (m *loxMap) delete(key interface{}) bool {
+	if _, ok := m.entries[key]; !ok {
+		return false
+	}
+	delete(m.entries, key)
+	for idx, k := range m.keys {
+		if isEqual(k, key) {
+			copy(m.keys[idx:], m.keys[idx+1:])
+			m.keys[len(m.keys)-1] = nil
+			m.keys = m.keys[:len(m.keys)-1]
+			break
+		}
+	}
+	return true
+}
+
+func (m *loxMap) String() string {
+	var b strings.Builder
+	b.WriteString("{")
+	for idx, key := range m.keys {
+		if idx > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s: %s", stringifyElement(key), stringifyElement(m.entries[key]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// checkMapKey returns an error if key can't be the key of a map. Keys are strings, numbers, booleans or nil,
+// which are hashed by the Go map consistently with isEqual: 0 and -0 are the same key. NaN is rejected since
+// it isn't equal to itself and couldn't be found again.
+func checkMapKey(key interface{}) error {
+	switch key := key.(type) {
+	case nil, bool, string:
+		return nil
+	case float64:
+		if math.IsNaN(key) {
+			return errors.New("Map key can't be NaN.")
+		}
+		return nil
+	}
+	return fmt.Errorf("Map key must be a string, a number, a boolean or nil, got %s.", KindOf(key))
+}
+
+// loxRange is the sequence of numbers going from start to end, excluded, by step. Ranges are immutable, so
+// two ranges are equal if they have the same bounds and step.
+type loxRange struct {
+	start, end, step float64
+}
+
+// length returns the number of numbers of the range.
+func (r loxRange) length() int {
+	n := math.Ceil((r.end - r.start) / r.step)
+	if n <= 0 {
+		return 0
+	}
+	return int(n)
+}
+
+func (r loxRange) String() string {
+	if r.step == 1 {
+		return fmt.Sprintf("range(%s, %s)", stringify(r.start), stringify(r.end))
+	}
+	return fmt.Sprintf("range(%s, %s, %s)", stringify(r.start), stringify(r.end), stringify(r.step))
+}
+
+// stringifyElement returns the representation of a value inside a collection, where strings are quoted so
+// that ["a, b"] and ["a", "b"] print differently.
+func stringifyElement(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return stringify(value)
+}
+
+// listIndex returns index as the index of one of the length elements of a list, or an error if it isn't one.
+func listIndex(index interface{}, length int) (int, error) {
+	return checkListIndex(index, length, length-1)
+}
+
+// listPosition is like listIndex, but also accepts the position right after the last element, where elements
+// can be inserted and slices can start or end.
+func listPosition(index interface{}, length int) (int, error) {
+	return checkListIndex(index, length, length)
+}
+
+func checkListIndex(index interface{}, length, max int) (int, error) {
+	n, ok := index.(float64)
+	if !ok {
+		return 0, fmt.Errorf("List index must be a number, got %s.", KindOf(index))
+	}
+	if n != math.Trunc(n) {
+		return 0, fmt.Errorf("List index must be an integer, got %s.", stringify(n))
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("List index can't be negative, got %s.", stringify(n))
+	}
+	if n > float64(max) {
+		return 0, fmt.Errorf("List index %s out of range for a list of length %d.", stringify(n), length)
+	}
+	return int(n), nil
+}
+
+// sliceBounds returns the bounds of a slice of a list of length elements. A nil start or end stands for the
+// start or the end of the list.
+func sliceBounds(start, end interface{}, length int) (int, int, error) {
+	from, to := 0, length
+	var err error
+	if start != nil {
+		if from, err = listPosition(start, length); err != nil {
+			return 0, 0, err
+		}
+	}
+	if end != nil {
+		if to, err = listPosition(end, length); err != nil {
+			return 0, 0, err
+		}
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("Slice start %d is after its end %d.", from, to)
+	}
+	return from, to, nil
+}
+
+// castList returns the list argument of the native function name, or an error if the argument is not a list.
+func castList(name string, argument interface{}) (*loxList, error) {
+	list, ok := argument.(*loxList)
+	if !ok {
+		return nil, fmt.Errorf("%s() expects a list, got %s.", name, KindOf(argument))
+	}
+	return list, nil
+}
+
+// defineListNatives adds the functions manipulating lists to the globals of the interpreter.
+func defineListNatives(i *interpreter) {
+	natives := []*nativeFunction{
+		{
+			name:       "len",
+			arityValue: 1,
+			function: func(arguments []interface{}) (interface{}, error) {
+				switch value := arguments[0].(type) {
+				case *loxList:
+					return float64(len(value.elements)), nil
+				case *loxMap:
+					return float64(len(value.keys)), nil
+				case loxRange:
+					return float64(value.length()), nil
+				case string:
+					return float64(utf8.RuneCountInString(value)), nil
+				}
+				return nil, fmt.Errorf("len() expects a list, a map, a range or a string, got %s.", KindOf(arguments[0]))
+			},
+		},
+		{
+			name:       "push",
+			arityValue: 2,
+			function: func(arguments []interface{}) (interface{}, error) {
+				list, err := castList("push", arguments[0])
+				if err != nil {
+					return nil, err
+				}
+				// the memory limit is checked by the next call or loop iteration
+				i.allocated += elementSize
+				list.elements = append(list.elements, arguments[1])
+				return nil, nil
+			},
+		},
+		{
+			name:       "pop",
+			arityValue: 1,
+			function: func(arguments []interface{}) (interface{}, error) {
+				list, err := castList("pop", arguments[0])
+				if err != nil {
+					return nil, err
+				}
+				if len(list.elements) == 0 {
+					return nil, errors.New("Can't pop from an empty list.")
+				}
+				last := list.elements[len(list.elements)-1]
+				list.elements[len(list.elements)-1] = nil
+				list.elements = list.elements[:len(list.elements)-1]
+				return last, nil
+			},
+		},
+		{
+			name:       "insert",
+			arityValue: 3,
+			function: func(arguments []interface{}) (interface{}, error) {
+				list, err := castList("insert", arguments[0])
+				if err != nil {
+					return nil, err
+				}
+				idx, err := listPosition(arguments[1], len(list.elements))
+				if err != nil {
+					return nil, err
+				}
+				i.allocated += elementSize
+				list.elements = append(list.elements, nil)
+				copy(list.elements[idx+1:], list.elements[idx:])
+				list.elements[idx] = arguments[2]
+				return nil, nil
+			},
+		},
+		{
+			name:       "remove",
+			arityValue: 2,
+			function: func(arguments []interface{}) (interface{}, error) {
+				list, err := castList("remove", arguments[0])
+				if err != nil {
+					return nil, err
+				}
+				idx, err := listIndex(arguments[1], len(list.elements))
+				if err != nil {
+					return nil, err
+				}
+				removed := list.elements[idx]
+				copy(list.elements[idx:], list.elements[idx+1:])
+				list.elements[len(list.elements)-1] = nil
+				list.elements = list.elements[:len(list.elements)-1]
+				return removed, nil
+			},
+		},
+	}
+	for _, native := range natives {
+		i.globals.define(native.name, native)
+	}
+}
+
+// castMap returns the map argument of the native function name, or an error if the argument is not a map.
+func castMap(name string, argument interface{}) (*loxMap, error) {
+	m, ok := argument.(*loxMap)
+	if !ok {
+		return nil, fmt.Errorf("%s() expects a map, got %s.", name, KindOf(argument))
+	}
+	return m, nil
+}
+
+// defineMapNatives adds the functions manipulating maps to the globals of the interpreter.
+func defineMapNatives(i *interpreter) {
+	natives := []*nativeFunction{
+		{
+			name:       "has",
+			arityValue: 2,
+			function: func(arguments []interface{}) (interface{}, error) {
+				m, err := castMap("has", arguments[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := checkMapKey(arguments[1]); err != nil {
+					return nil, err
+				}
+				_, ok := m.get(arguments[1])
+				return ok, nil
+			},
+		},
+		{
+			name:       "delete",
+			arityValue: 2,
+			function: func(arguments []interface{}) (interface{}, error) {
+				m, err := castMap("delete", arguments[0])
+				if err != nil {
+					return nil, err
+				}
+				if err := checkMapKey(arguments[1]); err != nil {
+					return nil, err
+				}
+				return m.delete(arguments[1]), nil
+			},
+		},
+		{
+			name:       "keys",
+			arityValue: 1,
+			function: func(arguments []interface{}) (interface{}, error) {
+				m, err := castMap("keys", arguments[0])
+				if err != nil {
+					return nil, err
+				}
+				i.allocated += listSize + len(m.keys)*elementSize
+				keys := make([]interface{}, len(m.keys))
+				copy(keys, m.keys)
+				return newLoxList(keys), nil
+			},
+		},
+		{
+			name:       "values",
+			arityValue: 1,
+			function: func(arguments []interface{}) (interface{}, error) {
+				m, err := castMap("values", arguments[0])
+				if err != nil {
+					return nil, err
+				}
+				i.allocated += listSize + len(m.keys)*elementSize
+				values := make([]interface{}, 0, len(m.keys))
+				for _, key := range m.keys {
+					values = append(values, m.entries[key])
+				}
+				return newLoxList(values), nil
+			},
+		},
+	}
+	for _, native := range natives {
+		i.globals.define(native.name, native)
+	}
+}
+
+// defineRangeNatives adds the functions creating ranges to the globals of the interpreter.
+func defineRangeNatives(i *interpreter) {
+	i.globals.define("range", &nativeFunction{
+		name:       "range",
+		arityValue: Variadic,
+		function: func(arguments []interface{}) (interface{}, error) {
+			if len(arguments) == 0 || len(arguments) > 3 {
+				return nil, fmt.Errorf("range() expects 1 to 3 arguments, got %d.", len(arguments))
+			}
+			numbers := make([]float64, 0, len(arguments))
+			for _, argument := range arguments {
+				n, ok := argument.(float64)
+				if !ok {
+					return nil, fmt.Errorf("range() expects numbers, got %s.", KindOf(argument))
+				}
+				if math.IsNaN(n) {
+					return nil, errors.New("range() expects numbers, got NaN.")
+				}
+				numbers = append(numbers, n)
+			}
+			// range(end) starts at 0 and range(start, end) goes by 1
+			r := loxRange{start: 0, end: numbers[0], step: 1}
+			if len(numbers) > 1 {
+				r.start, r.end = numbers[0], numbers[1]
+			}
+			if len(numbers) > 2 {
+				r.step = numbers[2]
+			}
+			if r.step == 0 {
+				return nil, errors.New("range() step can't be 0.")
+			}
+			return r, nil
+		},
+	})
+}
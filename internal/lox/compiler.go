@@ -0,0 +1,767 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nockty/glox/internal/diagnostics"
+)
+
+// maxLocals is the maximum number of local variables in scope at once in a function, and the maximum
+// number of upvalues a closure can capture, since both are addressed with a single byte.
+const maxLocals = math.MaxUint8 + 1
+
+type functionKind int
+
+const (
+	functionKindScript functionKind = iota
+	functionKindFunction
+	functionKindMethod
+	functionKindInitializer
+)
+
+type local struct {
+	name string
+	// depth is the scope depth of the block where the local is declared, or -1 while its initializer is
+	// being compiled.
+	depth      int
+	isCaptured bool
+}
+
+type upvalue struct {
+	// index is the stack slot of the captured local if isLocal, or the index of the upvalue of the
+	// enclosing function otherwise.
+	index   byte
+	isLocal bool
+}
+
+// loopCompiler holds the jumps of the break and continue statements of a loop being compiled, which are
+// patched once their target is known.
+type loopCompiler struct {
+	// scopeDepth is the scope depth outside of the loop: the locals declared deeper are discarded when
+	// jumping out of the body.
+	scopeDepth    int
+	breakJumps    []int
+	continueJumps []int
+}
+
+type classCompiler struct {
+	enclosing     *classCompiler
+	hasSuperclass bool
+}
+
+// functionCompiler holds the state of the function being compiled. Nested function declarations are
+// compiled with a new functionCompiler that points to the enclosing one.
+type functionCompiler struct {
+	enclosing  *functionCompiler
+	function   *objFunction
+	kind       functionKind
+	locals     []local
+	upvalues   []upvalue
+	scopeDepth int
+	// loops holds the loops enclosing the code being compiled, innermost last.
+	loops []*loopCompiler
+	// constants maps numbers and strings to their index in the constant pool so that they are only
+	// stored once.
+	constants map[interface{}]int
+}
+
+func newFunctionCompiler(enclosing *functionCompiler, kind functionKind, name string) *functionCompiler {
+	fc := &functionCompiler{
+		enclosing: enclosing,
+		function:  newObjFunction(name),
+		kind:      kind,
+		locals:    make([]local, 0, maxLocals),
+		upvalues:  make([]upvalue, 0),
+		constants: make(map[interface{}]int),
+	}
+	// the first stack slot of a call frame holds the function itself, or the receiver for methods
+	slotName := ""
+	if kind == functionKindMethod || kind == functionKindInitializer {
+		slotName = "this"
+	}
+	fc.locals = append(fc.locals, local{name: slotName, depth: 0})
+	return fc
+}
+
+// compiler compiles resolved statements to bytecode for the virtual machine.
+type compiler struct {
+	current      *functionCompiler
+	currentClass *classCompiler
+	// token is the last token seen, whose line is used for the line table of the chunk and for errors.
+	token Token
+
+	errors []*CompileError
+}
+
+// compiler implements visitorExpr and visitorStmt
+var _ visitorExpr = &compiler{}
+var _ visitorStmt = &compiler{}
+
+func NewCompiler() *compiler {
+	return &compiler{
+		token:  Token{Line: 1},
+		errors: make([]*CompileError, 0),
+	}
+}
+
+// Compile compiles a program to the function of the top-level script. The statements are expected to
+// have been resolved without errors.
+func (c *compiler) Compile(statements []Stmt) *objFunction {
+	c.current = newFunctionCompiler(nil, functionKindScript, "")
+	for _, statement := range statements {
+		c.compileStmt(statement)
+	}
+	return c.endFunction()
+}
+
+// HadErrors reports whether there were errors while compiling.
+func (c *compiler) HadErrors() bool {
+	return len(c.errors) > 0
+}
+
+// Errors returns the errors found while compiling, as *CompileError.
+func (c *compiler) Errors() []error {
+	result := make([]error, 0, len(c.errors))
+	for _, err := range c.errors {
+		result = append(result, err)
+	}
+	return result
+}
+
+func (c *compiler) compileStmt(stmt Stmt) {
+	stmt.Accept(c)
+}
+
+func (c *compiler) compileExpr(expr Expr) {
+	expr.Accept(c)
+}
+
+func (c *compiler) chunk() *chunk {
+	return c.current.function.chunk
+}
+
+func (c *compiler) emitByte(b byte) {
+	c.chunk().write(b, c.token.Line)
+}
+
+func (c *compiler) emitOp(op opCode) {
+	c.emitByte(byte(op))
+}
+
+func (c *compiler) emitShort(s int) {
+	c.emitByte(byte(s >> 8))
+	c.emitByte(byte(s))
+}
+
+func (c *compiler) emitOpShort(op opCode, s int) {
+	c.emitOp(op)
+	c.emitShort(s)
+}
+
+// emitJump emits a jump instruction with a placeholder offset and returns the position of the offset,
+// to be fixed later with patchJump.
+func (c *compiler) emitJump(op opCode) int {
+	c.emitOp(op)
+	c.emitShort(math.MaxUint16)
+	return len(c.chunk().code) - 2
+}
+
+func (c *compiler) patchJump(offset int) {
+	// -2 to adjust for the jump offset itself
+	jump := len(c.chunk().code) - offset - 2
+	if jump > math.MaxUint16 {
+		c.error("Too much code to jump over.")
+	}
+	c.chunk().code[offset] = byte(jump >> 8)
+	c.chunk().code[offset+1] = byte(jump)
+}
+
+func (c *compiler) emitLoop(loopStart int) {
+	c.emitOp(opLoop)
+	// +2 to jump over the loop offset too
+	offset := len(c.chunk().code) - loopStart + 2
+	if offset > math.MaxUint16 {
+		c.error("Loop body too large.")
+	}
+	c.emitShort(offset)
+}
+
+func (c *compiler) emitReturn() {
+	if c.current.kind == functionKindInitializer {
+		c.emitOp(opGetLocal)
+		c.emitByte(0)
+	} else {
+		c.emitOp(opNil)
+	}
+	c.emitOp(opReturn)
+}
+
+// makeConstant adds a value to the constant pool and returns its index. key deduplicates numbers and
+// strings; it is nil for values that should not be deduplicated.
+func (c *compiler) makeConstant(v value, key interface{}) int {
+	if key != nil {
+		if index, ok := c.current.constants[key]; ok {
+			return index
+		}
+	}
+	index := c.chunk().addConstant(v)
+	if index > math.MaxUint16 {
+		c.error("Too many constants in one chunk.")
+		return 0
+	}
+	if key != nil {
+		c.current.constants[key] = index
+	}
+	return index
+}
+
+func (c *compiler) stringConstant(s string) int {
+	return c.makeConstant(objValue(&objString{chars: s}), s)
+}
+
+func (c *compiler) endFunction() *objFunction {
+	c.emitReturn()
+	function := c.current.function
+	function.upvalueCount = len(c.current.upvalues)
+	c.current = c.current.enclosing
+	return function
+}
+
+func (c *compiler) beginScope() {
+	c.current.scopeDepth++
+}
+
+func (c *compiler) endScope() {
+	fc := c.current
+	fc.scopeDepth--
+	for len(fc.locals) > 0 && fc.locals[len(fc.locals)-1].depth > fc.scopeDepth {
+		if fc.locals[len(fc.locals)-1].isCaptured {
+			c.emitOp(opCloseUpvalue)
+		} else {
+			c.emitOp(opPop)
+		}
+		fc.locals = fc.locals[:len(fc.locals)-1]
+	}
+}
+
+// discardLocals emits the instructions that pop the locals declared deeper than depth, without removing them
+// from the scope, for statements that jump out of their scope.
+func (c *compiler) discardLocals(depth int) {
+	fc := c.current
+	for idx := len(fc.locals) - 1; idx >= 0 && fc.locals[idx].depth > depth; idx-- {
+		if fc.locals[idx].isCaptured {
+			c.emitOp(opCloseUpvalue)
+		} else {
+			c.emitOp(opPop)
+		}
+	}
+}
+
+func (c *compiler) addLocal(name string) {
+	if len(c.current.locals) == maxLocals {
+		c.error("Too many local variables in function.")
+		return
+	}
+	c.current.locals = append(c.current.locals, local{name: name, depth: -1})
+}
+
+// declareVariable adds a local variable to the current scope. Global variables are late bound so they
+// are not declared.
+func (c *compiler) declareVariable(name Token) {
+	c.token = name
+	if c.current.scopeDepth == 0 {
+		return
+	}
+	c.addLocal(name.Lexeme)
+}
+
+func (c *compiler) markInitialized() {
+	if c.current.scopeDepth == 0 {
+		return
+	}
+	c.current.locals[len(c.current.locals)-1].depth = c.current.scopeDepth
+}
+
+// defineVariable makes the variable whose value is on top of the stack available.
+func (c *compiler) defineVariable(name Token) {
+	if c.current.scopeDepth > 0 {
+		c.markInitialized()
+		return
+	}
+	c.emitOpShort(opDefineGlobal, c.stringConstant(name.Lexeme))
+}
+
+func resolveLocal(fc *functionCompiler, name string) int {
+	for i := len(fc.locals) - 1; i >= 0; i-- {
+		if fc.locals[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *compiler) resolveUpvalue(fc *functionCompiler, name string) int {
+	if fc.enclosing == nil {
+		return -1
+	}
+	if local := resolveLocal(fc.enclosing, name); local != -1 {
+		fc.enclosing.locals[local].isCaptured = true
+		return c.addUpvalue(fc, byte(local), true)
+	}
+	if upvalue := c.resolveUpvalue(fc.enclosing, name); upvalue != -1 {
+		return c.addUpvalue(fc, byte(upvalue), false)
+	}
+	return -1
+}
+
+func (c *compiler) addUpvalue(fc *functionCompiler, index byte, isLocal bool) int {
+	for i, upvalue := range fc.upvalues {
+		if upvalue.index == index && upvalue.isLocal == isLocal {
+			return i
+		}
+	}
+	if len(fc.upvalues) == maxLocals {
+		c.error("Too many closure variables in function.")
+		return 0
+	}
+	fc.upvalues = append(fc.upvalues, upvalue{index: index, isLocal: isLocal})
+	return len(fc.upvalues) - 1
+}
+
+// namedVariable emits the instructions to read the variable, or to assign it if value is not nil.
+func (c *compiler) namedVariable(name string, value Expr) {
+	var getOp, setOp opCode
+	var arg int
+	if slot := resolveLocal(c.current, name); slot != -1 {
+		getOp, setOp, arg = opGetLocal, opSetLocal, slot
+	} else if index := c.resolveUpvalue(c.current, name); index != -1 {
+		getOp, setOp, arg = opGetUpvalue, opSetUpvalue, index
+	} else {
+		index := c.stringConstant(name)
+		if value != nil {
+			c.compileExpr(value)
+			c.emitOpShort(opSetGlobal, index)
+		} else {
+			c.emitOpShort(opGetGlobal, index)
+		}
+		return
+	}
+	if value != nil {
+		c.compileExpr(value)
+		c.emitOp(setOp)
+	} else {
+		c.emitOp(getOp)
+	}
+	c.emitByte(byte(arg))
+}
+
+// function compiles the body of a function and emits the instruction that creates its closure.
+func (c *compiler) function(stmt *FunctionStmt, kind functionKind) {
+	c.current = newFunctionCompiler(c.current, kind, stmt.name.Lexeme)
+	c.beginScope()
+	c.current.function.arity = len(stmt.params)
+	for _, param := range stmt.params {
+		c.declareVariable(param)
+		c.defineVariable(param)
+	}
+	for _, statement := range stmt.body {
+		c.compileStmt(statement)
+	}
+	upvalues := c.current.upvalues
+	function := c.endFunction()
+
+	c.token = stmt.name
+	c.emitOpShort(opClosure, c.makeConstant(objValue(function), nil))
+	for _, upvalue := range upvalues {
+		if upvalue.isLocal {
+			c.emitByte(1)
+		} else {
+			c.emitByte(0)
+		}
+		c.emitByte(upvalue.index)
+	}
+}
+
+func (c *compiler) error(message string) {
+	c.errors = append(c.errors, &CompileError{Span: Span{File: c.token.File, Line: c.token.Line}, Message: message})
+}
+
+// unsupported reports a feature of the language that the virtual machine doesn't implement yet.
+func (c *compiler) unsupported(token Token, feature string) {
+	c.errors = append(c.errors, &CompileError{
+		Span:    token.Span(),
+		Message: fmt.Sprintf("%s are not supported by the virtual machine.", feature),
+	})
+}
+
+func (c *compiler) visitBlockStmt(stmt *BlockStmt) interface{} {
+	c.beginScope()
+	for _, statement := range stmt.statements {
+		c.compileStmt(statement)
+	}
+	c.endScope()
+	return nil
+}
+
+func (c *compiler) visitBreakStmt(stmt *BreakStmt) interface{} {
+	c.token = stmt.keyword
+	loop := c.current.loops[len(c.current.loops)-1]
+	c.discardLocals(loop.scopeDepth)
+	loop.breakJumps = append(loop.breakJumps, c.emitJump(opJump))
+	return nil
+}
+
+func (c *compiler) visitClassStmt(stmt *ClassStmt) interface{} {
+	c.token = stmt.name
+	nameConstant := c.stringConstant(stmt.name.Lexeme)
+	c.declareVariable(stmt.name)
+	c.emitOpShort(opClass, nameConstant)
+	c.defineVariable(stmt.name)
+
+	classCompiler := &classCompiler{enclosing: c.currentClass}
+	c.currentClass = classCompiler
+	defer func() { c.currentClass = classCompiler.enclosing }()
+
+	if stmt.superclass != nil {
+		c.compileExpr(stmt.superclass)
+		// the superclass is stored in a local "super" variable that methods capture as an upvalue
+		c.beginScope()
+		c.addLocal("super")
+		c.defineVariable(NewToken(Identifier, "super", nil, stmt.superclass.name.Line))
+		c.namedVariable(stmt.name.Lexeme, nil)
+		c.emitOp(opInherit)
+		classCompiler.hasSuperclass = true
+	}
+
+	// load the class so that methods can be bound to it
+	c.namedVariable(stmt.name.Lexeme, nil)
+	for _, method := range stmt.methods {
+		kind := functionKindMethod
+		if method.name.Lexeme == "init" {
+			kind = functionKindInitializer
+		}
+		c.function(method, kind)
+		c.emitOpShort(opMethod, c.stringConstant(method.name.Lexeme))
+	}
+	c.emitOp(opPop)
+
+	if classCompiler.hasSuperclass {
+		c.endScope()
+	}
+	return nil
+}
+
+func (c *compiler) visitContinueStmt(stmt *ContinueStmt) interface{} {
+	c.token = stmt.keyword
+	loop := c.current.loops[len(c.current.loops)-1]
+	c.discardLocals(loop.scopeDepth)
+	loop.continueJumps = append(loop.continueJumps, c.emitJump(opJump))
+	return nil
+}
+
+func (c *compiler) visitExpressionStmt(stmt *ExpressionStmt) interface{} {
+	c.compileExpr(stmt.expression)
+	c.emitOp(opPop)
+	return nil
+}
+
+func (c *compiler) visitForInStmt(stmt *ForInStmt) interface{} {
+	c.unsupported(stmt.in, "For-in loops")
+	return nil
+}
+
+func (c *compiler) visitFunctionStmt(stmt *FunctionStmt) interface{} {
+	c.declareVariable(stmt.name)
+	// a function can refer to itself recursively so it is initialized before its body is compiled
+	c.markInitialized()
+	c.function(stmt, functionKindFunction)
+	c.defineVariable(stmt.name)
+	return nil
+}
+
+func (c *compiler) visitIfStmt(stmt *IfStmt) interface{} {
+	c.compileExpr(stmt.condition)
+	thenJump := c.emitJump(opJumpIfFalse)
+	c.emitOp(opPop)
+	c.compileStmt(stmt.thenBranch)
+	elseJump := c.emitJump(opJump)
+	c.patchJump(thenJump)
+	c.emitOp(opPop)
+	if stmt.elseBranch != nil {
+		c.compileStmt(stmt.elseBranch)
+	}
+	c.patchJump(elseJump)
+	return nil
+}
+
+func (c *compiler) visitPrintStmt(stmt *PrintStmt) interface{} {
+	c.compileExpr(stmt.expression)
+	c.emitOp(opPrint)
+	return nil
+}
+
+func (c *compiler) visitReturnStmt(stmt *ReturnStmt) interface{} {
+	c.token = stmt.keyword
+	if stmt.value == nil {
+		c.emitReturn()
+		return nil
+	}
+	c.compileExpr(stmt.value)
+	c.emitOp(opReturn)
+	return nil
+}
+
+func (c *compiler) visitThrowStmt(stmt *ThrowStmt) interface{} {
+	c.unsupported(stmt.keyword, "Exceptions")
+	return nil
+}
+
+func (c *compiler) visitTryStmt(stmt *TryStmt) interface{} {
+	c.unsupported(stmt.keyword, "Exceptions")
+	return nil
+}
+
+func (c *compiler) visitVarStmt(stmt *VarStmt) interface{} {
+	c.declareVariable(stmt.name)
+	if stmt.initializer != nil {
+		c.compileExpr(stmt.initializer)
+	} else {
+		c.emitOp(opNil)
+	}
+	c.defineVariable(stmt.name)
+	return nil
+}
+
+func (c *compiler) visitWhileStmt(stmt *WhileStmt) interface{} {
+	loopStart := len(c.chunk().code)
+	c.compileExpr(stmt.condition)
+	exitJump := c.emitJump(opJumpIfFalse)
+	c.emitOp(opPop)
+
+	loop := &loopCompiler{scopeDepth: c.current.scopeDepth}
+	c.current.loops = append(c.current.loops, loop)
+	c.compileStmt(stmt.body)
+	c.current.loops = c.current.loops[:len(c.current.loops)-1]
+
+	// continue statements jump to the increment, if any, and then back to the condition
+	for _, jump := range loop.continueJumps {
+		c.patchJump(jump)
+	}
+	if stmt.increment != nil {
+		c.compileExpr(stmt.increment)
+		c.emitOp(opPop)
+	}
+	c.emitLoop(loopStart)
+	c.patchJump(exitJump)
+	c.emitOp(opPop)
+	// break statements jump past the pop of the condition, which they don't leave on the stack
+	for _, jump := range loop.breakJumps {
+		c.patchJump(jump)
+	}
+	return nil
+}
+
+func (c *compiler) visitAssignExpr(expr *AssignExpr) interface{} {
+	c.token = expr.name
+	c.namedVariable(expr.name.Lexeme, expr.value)
+	return nil
+}
+
+func (c *compiler) visitBinaryExpr(expr *BinaryExpr) interface{} {
+	c.compileExpr(expr.left)
+	c.compileExpr(expr.right)
+	c.token = expr.operator
+	switch expr.operator.Type {
+	case EqualEqual:
+		c.emitOp(opEqual)
+	case BangEqual:
+		c.emitOp(opNotEqual)
+	case Greater:
+		c.emitOp(opGreater)
+	case GreaterEqual:
+		c.emitOp(opGreaterEqual)
+	case Less:
+		c.emitOp(opLess)
+	case LessEqual:
+		c.emitOp(opLessEqual)
+	case Plus:
+		c.emitOp(opAdd)
+	case Minus:
+		c.emitOp(opSubtract)
+	case Star:
+		c.emitOp(opMultiply)
+	case Slash:
+		c.emitOp(opDivide)
+	}
+	return nil
+}
+
+func (c *compiler) visitCallExpr(expr *CallExpr) interface{} {
+	switch callee := expr.callee.(type) {
+	case *GetExpr:
+		// call the method directly instead of creating a bound method first
+		c.compileExpr(callee.object)
+		c.compileArguments(expr.arguments)
+		c.token = expr.paren
+		c.emitOpShort(opInvoke, c.stringConstant(callee.name.Lexeme))
+	case *SuperExpr:
+		c.token = callee.keyword
+		c.namedVariable("this", nil)
+		c.compileArguments(expr.arguments)
+		c.namedVariable("super", nil)
+		c.token = expr.paren
+		c.emitOpShort(opSuperInvoke, c.stringConstant(callee.method.Lexeme))
+	default:
+		c.compileExpr(expr.callee)
+		c.compileArguments(expr.arguments)
+		c.token = expr.paren
+		c.emitOp(opCall)
+	}
+	c.emitByte(byte(len(expr.arguments)))
+	return nil
+}
+
+func (c *compiler) compileArguments(arguments []Expr) {
+	for _, argument := range arguments {
+		c.compileExpr(argument)
+	}
+}
+
+func (c *compiler) visitGetExpr(expr *GetExpr) interface{} {
+	c.compileExpr(expr.object)
+	c.token = expr.name
+	c.emitOpShort(opGetProperty, c.stringConstant(expr.name.Lexeme))
+	return nil
+}
+
+func (c *compiler) visitGroupingExpr(expr *GroupingExpr) interface{} {
+	c.compileExpr(expr.expression)
+	return nil
+}
+
+func (c *compiler) visitLiteralExpr(expr *LiteralExpr) interface{} {
+	switch literal := expr.value.(type) {
+	case nil:
+		c.emitOp(opNil)
+	case bool:
+		if literal {
+			c.emitOp(opTrue)
+		} else {
+			c.emitOp(opFalse)
+		}
+	case float64:
+		c.emitOpShort(opConstant, c.makeConstant(numberValue(literal), literal))
+	case string:
+		c.emitOpShort(opConstant, c.stringConstant(literal))
+	default:
+		c.error(fmt.Sprintf("Unsupported literal %v.", literal))
+	}
+	return nil
+}
+
+func (c *compiler) visitLogicalExpr(expr *LogicalExpr) interface{} {
+	c.compileExpr(expr.left)
+	c.token = expr.operator
+	if expr.operator.Type == Or {
+		elseJump := c.emitJump(opJumpIfFalse)
+		endJump := c.emitJump(opJump)
+		c.patchJump(elseJump)
+		c.emitOp(opPop)
+		c.compileExpr(expr.right)
+		c.patchJump(endJump)
+		return nil
+	}
+	endJump := c.emitJump(opJumpIfFalse)
+	c.emitOp(opPop)
+	c.compileExpr(expr.right)
+	c.patchJump(endJump)
+	return nil
+}
+
+func (c *compiler) visitIndexExpr(expr *IndexExpr) interface{} {
+	c.unsupported(expr.bracket, "Lists and maps")
+	return nil
+}
+
+func (c *compiler) visitIndexSetExpr(expr *IndexSetExpr) interface{} {
+	c.unsupported(expr.bracket, "Lists and maps")
+	return nil
+}
+
+func (c *compiler) visitListExpr(expr *ListExpr) interface{} {
+	c.unsupported(expr.bracket, "Lists")
+	return nil
+}
+
+func (c *compiler) visitMapExpr(expr *MapExpr) interface{} {
+	c.unsupported(expr.brace, "Maps")
+	return nil
+}
+
+func (c *compiler) visitSliceExpr(expr *SliceExpr) interface{} {
+	c.unsupported(expr.bracket, "Lists")
+	return nil
+}
+
+func (c *compiler) visitSetExpr(expr *SetExpr) interface{} {
+	c.compileExpr(expr.object)
+	c.compileExpr(expr.value)
+	c.token = expr.name
+	c.emitOpShort(opSetProperty, c.stringConstant(expr.name.Lexeme))
+	return nil
+}
+
+func (c *compiler) visitSuperExpr(expr *SuperExpr) interface{} {
+	c.token = expr.keyword
+	c.namedVariable("this", nil)
+	c.namedVariable("super", nil)
+	c.emitOpShort(opGetSuper, c.stringConstant(expr.method.Lexeme))
+	return nil
+}
+
+func (c *compiler) visitThisExpr(expr *ThisExpr) interface{} {
+	c.token = expr.keyword
+	c.namedVariable("this", nil)
+	return nil
+}
+
+func (c *compiler) visitUnaryExpr(expr *UnaryExpr) interface{} {
+	c.compileExpr(expr.right)
+	c.token = expr.operator
+	switch expr.operator.Type {
+	case Bang:
+		c.emitOp(opNot)
+	case Minus:
+		c.emitOp(opNegate)
+	}
+	return nil
+}
+
+func (c *compiler) visitVariableExpr(expr *VariableExpr) interface{} {
+	c.token = expr.name
+	c.namedVariable(expr.name.Lexeme, nil)
+	return nil
+}
+
+// CompileError is an error found when compiling to bytecode, which is usually a limit of the virtual
+// machine.
+type CompileError struct {
+	// Span only locates the line of the error: the compiler doesn't keep track of columns. Its fields, such
+	// as Line, are promoted.
+	Span
+	Message string
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("[%s] Compile Error: %s", e.Span, e.Message)
+}
+
+func (e *CompileError) diagnostic() diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Severity: diagnostics.Error,
+		Code:     "compile-error",
+		Message:  e.Message,
+		Span:     e.Span,
+	}
+}
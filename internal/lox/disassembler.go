@@ -0,0 +1,108 @@
+package lox
+
+import (
+	"fmt"
+	"io"
+)
+
+// Disassembler prints the bytecode of compiled functions in a human-readable form. It is the bytecode
+// counterpart of AstPrinter.
+type Disassembler struct {
+	w io.Writer
+}
+
+func NewDisassembler(w io.Writer) *Disassembler {
+	return &Disassembler{w: w}
+}
+
+// Disassemble prints the chunk of the function, then the chunks of the functions declared in it.
+func (d *Disassembler) Disassemble(function *objFunction) {
+	d.disassembleChunk(function.chunk, function.String())
+	for _, constant := range function.chunk.constants {
+		if nested, ok := constant.obj.(*objFunction); ok {
+			fmt.Fprintln(d.w)
+			d.Disassemble(nested)
+		}
+	}
+}
+
+func (d *Disassembler) disassembleChunk(c *chunk, name string) {
+	fmt.Fprintf(d.w, "== %s ==\n", name)
+	for offset := 0; offset < len(c.code); {
+		offset = d.disassembleInstruction(c, offset)
+	}
+}
+
+// disassembleInstruction prints the instruction at offset and returns the offset of the next one.
+func (d *Disassembler) disassembleInstruction(c *chunk, offset int) int {
+	fmt.Fprintf(d.w, "%04d ", offset)
+	if offset > 0 && c.lines[offset] == c.lines[offset-1] {
+		fmt.Fprint(d.w, "   | ")
+	} else {
+		fmt.Fprintf(d.w, "%4d ", c.lines[offset])
+	}
+
+	op := opCode(c.code[offset])
+	switch op {
+	case opConstant, opGetGlobal, opDefineGlobal, opSetGlobal, opGetProperty, opSetProperty, opGetSuper,
+		opClass, opMethod:
+		return d.constantInstruction(op, c, offset)
+	case opGetLocal, opSetLocal, opGetUpvalue, opSetUpvalue, opCall:
+		return d.byteInstruction(op, c, offset)
+	case opJump, opJumpIfFalse:
+		return d.jumpInstruction(op, 1, c, offset)
+	case opLoop:
+		return d.jumpInstruction(op, -1, c, offset)
+	case opInvoke, opSuperInvoke:
+		return d.invokeInstruction(op, c, offset)
+	case opClosure:
+		return d.closureInstruction(op, c, offset)
+	default:
+		fmt.Fprintln(d.w, op)
+		return offset + 1
+	}
+}
+
+func (d *Disassembler) constantInstruction(op opCode, c *chunk, offset int) int {
+	constant := readShort(c, offset+1)
+	fmt.Fprintf(d.w, "%-16s %4d '%s'\n", op, constant, c.constants[constant])
+	return offset + 3
+}
+
+func (d *Disassembler) byteInstruction(op opCode, c *chunk, offset int) int {
+	fmt.Fprintf(d.w, "%-16s %4d\n", op, c.code[offset+1])
+	return offset + 2
+}
+
+func (d *Disassembler) jumpInstruction(op opCode, sign int, c *chunk, offset int) int {
+	jump := readShort(c, offset+1)
+	fmt.Fprintf(d.w, "%-16s %4d -> %d\n", op, offset, offset+3+sign*jump)
+	return offset + 3
+}
+
+func (d *Disassembler) invokeInstruction(op opCode, c *chunk, offset int) int {
+	constant := readShort(c, offset+1)
+	argCount := c.code[offset+3]
+	fmt.Fprintf(d.w, "%-16s (%d args) %4d '%s'\n", op, argCount, constant, c.constants[constant])
+	return offset + 4
+}
+
+func (d *Disassembler) closureInstruction(op opCode, c *chunk, offset int) int {
+	constant := readShort(c, offset+1)
+	function := c.constants[constant].obj.(*objFunction)
+	fmt.Fprintf(d.w, "%-16s %4d %s\n", op, constant, function)
+	offset += 3
+	for i := 0; i < function.upvalueCount; i++ {
+		kind := "upvalue"
+		if c.code[offset] == 1 {
+			kind = "local"
+		}
+		fmt.Fprintf(d.w, "%04d    |                     %s %d\n", offset, kind, c.code[offset+1])
+		offset += 2
+	}
+	return offset
+}
+
+func readShort(c *chunk, offset int) int {
+	return int(c.code[offset])<<8 | int(c.code[offset+1])
+}
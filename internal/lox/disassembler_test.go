@@ -0,0 +1,47 @@
+package lox
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisassembler(t *testing.T) {
+	source := `var a = 1;
+if (a > 0) {
+  fun f(x) {
+    return x + a;
+  }
+  print f(2);
+}`
+	expected := `== <script> ==
+0000    1 OP_CONSTANT         0 '1'
+0003    | OP_DEFINE_GLOBAL    1 'a'
+0006    2 OP_GET_GLOBAL       1 'a'
+0009    | OP_CONSTANT         2 '0'
+0012    | OP_GREATER
+0013    | OP_JUMP_IF_FALSE   13 -> 32
+0016    | OP_POP
+0017    3 OP_CLOSURE          3 <fn f>
+0020    6 OP_GET_LOCAL        1
+0022    | OP_CONSTANT         4 '2'
+0025    | OP_CALL             1
+0027    | OP_PRINT
+0028    | OP_POP
+0029    | OP_JUMP            29 -> 33
+0032    | OP_POP
+0033    | OP_NIL
+0034    | OP_RETURN
+`
+	statements, _ := resolve(t, source)
+	compiler := NewCompiler()
+	function := compiler.Compile(statements)
+	require.False(t, compiler.HadErrors())
+
+	var output bytes.Buffer
+	NewDisassembler(&output).Disassemble(function)
+	assert.Contains(t, output.String(), "== <fn f> ==\n0000    4 OP_GET_LOCAL        1\n")
+	assert.Equal(t, expected, output.String()[:len(expected)])
+}
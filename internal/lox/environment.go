@@ -25,30 +25,49 @@ func (e *environment) define(name string, value interface{}) {
 	e.values[name] = value
 }
 
-func (e *environment) assign(name Token, value interface{}) *runtimeError {
+func (e *environment) assign(name Token, value interface{}) *RuntimeError {
 	if _, ok := e.values[name.Lexeme]; !ok {
 		if e.enclosing != nil {
 			return e.enclosing.assign(name, value)
 		}
-		return &runtimeError{
-			token:   name,
-			message: fmt.Sprintf("Undefined variable '%s'.", name.Lexeme),
+		return &RuntimeError{
+			Token:   name,
+			Message: fmt.Sprintf("Undefined variable '%s'.", name.Lexeme),
 		}
 	}
 	e.values[name.Lexeme] = value
 	return nil
 }
 
-func (e *environment) get(name Token) (interface{}, *runtimeError) {
+func (e *environment) get(name Token) (interface{}, *RuntimeError) {
 	value, ok := e.values[name.Lexeme]
 	if !ok {
 		if e.enclosing != nil {
 			return e.enclosing.get(name)
 		}
-		return nil, &runtimeError{
-			token:   name,
-			message: fmt.Sprintf("Undefined variable '%s'.", name.Lexeme),
+		return nil, &RuntimeError{
+			Token:   name,
+			Message: fmt.Sprintf("Undefined variable '%s'.", name.Lexeme),
 		}
 	}
 	return value, nil
 }
+
+// getAt returns the value of a variable declared distance scopes away. The resolver guarantees that it
+// exists.
+func (e *environment) getAt(distance int, name string) interface{} {
+	return e.ancestor(distance).values[name]
+}
+
+// assignAt assigns a variable declared distance scopes away. The resolver guarantees that it exists.
+func (e *environment) assignAt(distance int, name Token, value interface{}) {
+	e.ancestor(distance).values[name.Lexeme] = value
+}
+
+func (e *environment) ancestor(distance int) *environment {
+	env := e
+	for i := 0; i < distance; i++ {
+		env = env.enclosing
+	}
+	return env
+}
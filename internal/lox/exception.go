@@ -0,0 +1,104 @@
+package lox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errorPrelude declares the class of error objects. Scripts create them with a message to throw them, and
+// catch clauses receive them for the runtime errors of the interpreter. Error objects also have the line and
+// the stack trace of the place they were first thrown from once caught.
+const errorPrelude = `
+class Error {
+  init(message) {
+    this.message = message;
+  }
+}`
+
+// defineErrorClass adds the class of error objects to the globals of the interpreter.
+func defineErrorClass(i *interpreter) {
+	statements, err := Parse("", errorPrelude)
+	if err != nil {
+		panic(fmt.Sprintf("invalid error prelude: %v", err))
+	}
+	NewResolver(i).Resolve(statements)
+	for _, statement := range statements {
+		i.execute(statement)
+	}
+	i.errorClass = i.globals.getAt(0, "Error").(*loxClass)
+}
+
+// interpreterFrame is a call in progress in the tree-walk interpreter.
+type interpreterFrame struct {
+	function LoxCallable
+	// call is the token where the function was called.
+	call Token
+}
+
+// stackTrace returns the stack trace of an error happening at line in the innermost call in progress, in the
+// format of the virtual machine.
+func (i *interpreter) stackTrace(line int) string {
+	var b strings.Builder
+	for idx := len(i.frames) - 1; idx >= 0; idx-- {
+		fmt.Fprintf(&b, "[line %d] in %s()\n", line, callableName(i.frames[idx].function))
+		line = i.frames[idx].call.Line
+	}
+	fmt.Fprintf(&b, "[line %d] in script", line)
+	return b.String()
+}
+
+func callableName(function LoxCallable) string {
+	switch function := function.(type) {
+	case *loxFunction:
+		return function.declaration.name.Lexeme
+	case *nativeFunction:
+		return function.name
+	case *loxClass:
+		return function.name
+	}
+	return stringify(function)
+}
+
+// catchable reports whether catch clauses can handle the error. Errors stopping a run because it exceeds its
+// limits or its context is done can't be caught, so that scripts can't escape them.
+func (e *RuntimeError) catchable() bool {
+	for _, target := range []error{ErrStepLimit, ErrStackOverflow, ErrMemoryLimit, context.Canceled, context.DeadlineExceeded} {
+		if errors.Is(e, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// thrownMessage returns the message of the runtime error raised by throwing value when it isn't caught: the
+// message of error objects, or the representation of other values.
+func (i *interpreter) thrownMessage(value interface{}) string {
+	if instance, ok := value.(*loxInstance); ok && instance.class.isSubclassOf(i.errorClass) {
+		return stringify(instance.fields["message"])
+	}
+	return stringify(value)
+}
+
+// caughtValue returns the value a catch clause receives for err: the thrown value, or an error object for
+// the runtime errors of the interpreter.
+func (i *interpreter) caughtValue(err *RuntimeError) interface{} {
+	if !err.thrown {
+		i.allocated += instanceSize + 3*entrySize
+		instance := newLoxInstance(i.errorClass)
+		instance.fields["message"] = err.Message
+		instance.fields["line"] = float64(err.Token.Line)
+		instance.fields["stack"] = err.stack
+		return instance
+	}
+	if instance, ok := err.value.(*loxInstance); ok && instance.class.isSubclassOf(i.errorClass) {
+		// an error object rethrown by a catch clause keeps the place it was first thrown from
+		if _, ok := instance.fields["stack"]; !ok {
+			i.allocated += 2 * entrySize
+			instance.fields["line"] = float64(err.Token.Line)
+			instance.fields["stack"] = err.stack
+		}
+	}
+	return err.value
+}
@@ -2,6 +2,9 @@
 package lox
 
 type Expr interface {
+	// Span returns the location of the node in the source.
+	Span() Span
+	setSpan(span Span)
 	Accept(v visitorExpr) interface{}
 	AcceptBool(v visitorExprBool) bool
 	AcceptString(v visitorExprString) string
@@ -27,9 +30,19 @@ type Expr interface {
 type visitorExpr interface {
 	visitAssignExpr(*AssignExpr) interface{}
 	visitBinaryExpr(*BinaryExpr) interface{}
+	visitCallExpr(*CallExpr) interface{}
+	visitGetExpr(*GetExpr) interface{}
 	visitGroupingExpr(*GroupingExpr) interface{}
+	visitIndexExpr(*IndexExpr) interface{}
+	visitIndexSetExpr(*IndexSetExpr) interface{}
+	visitListExpr(*ListExpr) interface{}
 	visitLiteralExpr(*LiteralExpr) interface{}
 	visitLogicalExpr(*LogicalExpr) interface{}
+	visitMapExpr(*MapExpr) interface{}
+	visitSetExpr(*SetExpr) interface{}
+	visitSliceExpr(*SliceExpr) interface{}
+	visitSuperExpr(*SuperExpr) interface{}
+	visitThisExpr(*ThisExpr) interface{}
 	visitUnaryExpr(*UnaryExpr) interface{}
 	visitVariableExpr(*VariableExpr) interface{}
 }
@@ -37,9 +50,19 @@ type visitorExpr interface {
 type visitorExprBool interface {
 	visitAssignExpr(*AssignExpr) bool
 	visitBinaryExpr(*BinaryExpr) bool
+	visitCallExpr(*CallExpr) bool
+	visitGetExpr(*GetExpr) bool
 	visitGroupingExpr(*GroupingExpr) bool
+	visitIndexExpr(*IndexExpr) bool
+	visitIndexSetExpr(*IndexSetExpr) bool
+	visitListExpr(*ListExpr) bool
 	visitLiteralExpr(*LiteralExpr) bool
 	visitLogicalExpr(*LogicalExpr) bool
+	visitMapExpr(*MapExpr) bool
+	visitSetExpr(*SetExpr) bool
+	visitSliceExpr(*SliceExpr) bool
+	visitSuperExpr(*SuperExpr) bool
+	visitThisExpr(*ThisExpr) bool
 	visitUnaryExpr(*UnaryExpr) bool
 	visitVariableExpr(*VariableExpr) bool
 }
@@ -47,9 +70,19 @@ type visitorExprBool interface {
 type visitorExprString interface {
 	visitAssignExpr(*AssignExpr) string
 	visitBinaryExpr(*BinaryExpr) string
+	visitCallExpr(*CallExpr) string
+	visitGetExpr(*GetExpr) string
 	visitGroupingExpr(*GroupingExpr) string
+	visitIndexExpr(*IndexExpr) string
+	visitIndexSetExpr(*IndexSetExpr) string
+	visitListExpr(*ListExpr) string
 	visitLiteralExpr(*LiteralExpr) string
 	visitLogicalExpr(*LogicalExpr) string
+	visitMapExpr(*MapExpr) string
+	visitSetExpr(*SetExpr) string
+	visitSliceExpr(*SliceExpr) string
+	visitSuperExpr(*SuperExpr) string
+	visitThisExpr(*ThisExpr) string
 	visitUnaryExpr(*UnaryExpr) string
 	visitVariableExpr(*VariableExpr) string
 }
@@ -57,9 +90,19 @@ type visitorExprString interface {
 type visitorExprInt interface {
 	visitAssignExpr(*AssignExpr) int
 	visitBinaryExpr(*BinaryExpr) int
+	visitCallExpr(*CallExpr) int
+	visitGetExpr(*GetExpr) int
 	visitGroupingExpr(*GroupingExpr) int
+	visitIndexExpr(*IndexExpr) int
+	visitIndexSetExpr(*IndexSetExpr) int
+	visitListExpr(*ListExpr) int
 	visitLiteralExpr(*LiteralExpr) int
 	visitLogicalExpr(*LogicalExpr) int
+	visitMapExpr(*MapExpr) int
+	visitSetExpr(*SetExpr) int
+	visitSliceExpr(*SliceExpr) int
+	visitSuperExpr(*SuperExpr) int
+	visitThisExpr(*ThisExpr) int
 	visitUnaryExpr(*UnaryExpr) int
 	visitVariableExpr(*VariableExpr) int
 }
@@ -67,9 +110,19 @@ type visitorExprInt interface {
 type visitorExprInt8 interface {
 	visitAssignExpr(*AssignExpr) int8
 	visitBinaryExpr(*BinaryExpr) int8
+	visitCallExpr(*CallExpr) int8
+	visitGetExpr(*GetExpr) int8
 	visitGroupingExpr(*GroupingExpr) int8
+	visitIndexExpr(*IndexExpr) int8
+	visitIndexSetExpr(*IndexSetExpr) int8
+	visitListExpr(*ListExpr) int8
 	visitLiteralExpr(*LiteralExpr) int8
 	visitLogicalExpr(*LogicalExpr) int8
+	visitMapExpr(*MapExpr) int8
+	visitSetExpr(*SetExpr) int8
+	visitSliceExpr(*SliceExpr) int8
+	visitSuperExpr(*SuperExpr) int8
+	visitThisExpr(*ThisExpr) int8
 	visitUnaryExpr(*UnaryExpr) int8
 	visitVariableExpr(*VariableExpr) int8
 }
@@ -77,9 +130,19 @@ type visitorExprInt8 interface {
 type visitorExprInt16 interface {
 	visitAssignExpr(*AssignExpr) int16
 	visitBinaryExpr(*BinaryExpr) int16
+	visitCallExpr(*CallExpr) int16
+	visitGetExpr(*GetExpr) int16
 	visitGroupingExpr(*GroupingExpr) int16
+	visitIndexExpr(*IndexExpr) int16
+	visitIndexSetExpr(*IndexSetExpr) int16
+	visitListExpr(*ListExpr) int16
 	visitLiteralExpr(*LiteralExpr) int16
 	visitLogicalExpr(*LogicalExpr) int16
+	visitMapExpr(*MapExpr) int16
+	visitSetExpr(*SetExpr) int16
+	visitSliceExpr(*SliceExpr) int16
+	visitSuperExpr(*SuperExpr) int16
+	visitThisExpr(*ThisExpr) int16
 	visitUnaryExpr(*UnaryExpr) int16
 	visitVariableExpr(*VariableExpr) int16
 }
@@ -87,9 +150,19 @@ type visitorExprInt16 interface {
 type visitorExprInt32 interface {
 	visitAssignExpr(*AssignExpr) int32
 	visitBinaryExpr(*BinaryExpr) int32
+	visitCallExpr(*CallExpr) int32
+	visitGetExpr(*GetExpr) int32
 	visitGroupingExpr(*GroupingExpr) int32
+	visitIndexExpr(*IndexExpr) int32
+	visitIndexSetExpr(*IndexSetExpr) int32
+	visitListExpr(*ListExpr) int32
 	visitLiteralExpr(*LiteralExpr) int32
 	visitLogicalExpr(*LogicalExpr) int32
+	visitMapExpr(*MapExpr) int32
+	visitSetExpr(*SetExpr) int32
+	visitSliceExpr(*SliceExpr) int32
+	visitSuperExpr(*SuperExpr) int32
+	visitThisExpr(*ThisExpr) int32
 	visitUnaryExpr(*UnaryExpr) int32
 	visitVariableExpr(*VariableExpr) int32
 }
@@ -97,9 +170,19 @@ type visitorExprInt32 interface {
 type visitorExprInt64 interface {
 	visitAssignExpr(*AssignExpr) int64
 	visitBinaryExpr(*BinaryExpr) int64
+	visitCallExpr(*CallExpr) int64
+	visitGetExpr(*GetExpr) int64
 	visitGroupingExpr(*GroupingExpr) int64
+	visitIndexExpr(*IndexExpr) int64
+	visitIndexSetExpr(*IndexSetExpr) int64
+	visitListExpr(*ListExpr) int64
 	visitLiteralExpr(*LiteralExpr) int64
 	visitLogicalExpr(*LogicalExpr) int64
+	visitMapExpr(*MapExpr) int64
+	visitSetExpr(*SetExpr) int64
+	visitSliceExpr(*SliceExpr) int64
+	visitSuperExpr(*SuperExpr) int64
+	visitThisExpr(*ThisExpr) int64
 	visitUnaryExpr(*UnaryExpr) int64
 	visitVariableExpr(*VariableExpr) int64
 }
@@ -107,9 +190,19 @@ type visitorExprInt64 interface {
 type visitorExprUint interface {
 	visitAssignExpr(*AssignExpr) uint
 	visitBinaryExpr(*BinaryExpr) uint
+	visitCallExpr(*CallExpr) uint
+	visitGetExpr(*GetExpr) uint
 	visitGroupingExpr(*GroupingExpr) uint
+	visitIndexExpr(*IndexExpr) uint
+	visitIndexSetExpr(*IndexSetExpr) uint
+	visitListExpr(*ListExpr) uint
 	visitLiteralExpr(*LiteralExpr) uint
 	visitLogicalExpr(*LogicalExpr) uint
+	visitMapExpr(*MapExpr) uint
+	visitSetExpr(*SetExpr) uint
+	visitSliceExpr(*SliceExpr) uint
+	visitSuperExpr(*SuperExpr) uint
+	visitThisExpr(*ThisExpr) uint
 	visitUnaryExpr(*UnaryExpr) uint
 	visitVariableExpr(*VariableExpr) uint
 }
@@ -117,9 +210,19 @@ type visitorExprUint interface {
 type visitorExprUint8 interface {
 	visitAssignExpr(*AssignExpr) uint8
 	visitBinaryExpr(*BinaryExpr) uint8
+	visitCallExpr(*CallExpr) uint8
+	visitGetExpr(*GetExpr) uint8
 	visitGroupingExpr(*GroupingExpr) uint8
+	visitIndexExpr(*IndexExpr) uint8
+	visitIndexSetExpr(*IndexSetExpr) uint8
+	visitListExpr(*ListExpr) uint8
 	visitLiteralExpr(*LiteralExpr) uint8
 	visitLogicalExpr(*LogicalExpr) uint8
+	visitMapExpr(*MapExpr) uint8
+	visitSetExpr(*SetExpr) uint8
+	visitSliceExpr(*SliceExpr) uint8
+	visitSuperExpr(*SuperExpr) uint8
+	visitThisExpr(*ThisExpr) uint8
 	visitUnaryExpr(*UnaryExpr) uint8
 	visitVariableExpr(*VariableExpr) uint8
 }
@@ -127,9 +230,19 @@ type visitorExprUint8 interface {
 type visitorExprUint16 interface {
 	visitAssignExpr(*AssignExpr) uint16
 	visitBinaryExpr(*BinaryExpr) uint16
+	visitCallExpr(*CallExpr) uint16
+	visitGetExpr(*GetExpr) uint16
 	visitGroupingExpr(*GroupingExpr) uint16
+	visitIndexExpr(*IndexExpr) uint16
+	visitIndexSetExpr(*IndexSetExpr) uint16
+	visitListExpr(*ListExpr) uint16
 	visitLiteralExpr(*LiteralExpr) uint16
 	visitLogicalExpr(*LogicalExpr) uint16
+	visitMapExpr(*MapExpr) uint16
+	visitSetExpr(*SetExpr) uint16
+	visitSliceExpr(*SliceExpr) uint16
+	visitSuperExpr(*SuperExpr) uint16
+	visitThisExpr(*ThisExpr) uint16
 	visitUnaryExpr(*UnaryExpr) uint16
 	visitVariableExpr(*VariableExpr) uint16
 }
@@ -137,9 +250,19 @@ type visitorExprUint16 interface {
 type visitorExprUint32 interface {
 	visitAssignExpr(*AssignExpr) uint32
 	visitBinaryExpr(*BinaryExpr) uint32
+	visitCallExpr(*CallExpr) uint32
+	visitGetExpr(*GetExpr) uint32
 	visitGroupingExpr(*GroupingExpr) uint32
+	visitIndexExpr(*IndexExpr) uint32
+	visitIndexSetExpr(*IndexSetExpr) uint32
+	visitListExpr(*ListExpr) uint32
 	visitLiteralExpr(*LiteralExpr) uint32
 	visitLogicalExpr(*LogicalExpr) uint32
+	visitMapExpr(*MapExpr) uint32
+	visitSetExpr(*SetExpr) uint32
+	visitSliceExpr(*SliceExpr) uint32
+	visitSuperExpr(*SuperExpr) uint32
+	visitThisExpr(*ThisExpr) uint32
 	visitUnaryExpr(*UnaryExpr) uint32
 	visitVariableExpr(*VariableExpr) uint32
 }
@@ -147,9 +270,19 @@ type visitorExprUint32 interface {
 type visitorExprUint64 interface {
 	visitAssignExpr(*AssignExpr) uint64
 	visitBinaryExpr(*BinaryExpr) uint64
+	visitCallExpr(*CallExpr) uint64
+	visitGetExpr(*GetExpr) uint64
 	visitGroupingExpr(*GroupingExpr) uint64
+	visitIndexExpr(*IndexExpr) uint64
+	visitIndexSetExpr(*IndexSetExpr) uint64
+	visitListExpr(*ListExpr) uint64
 	visitLiteralExpr(*LiteralExpr) uint64
 	visitLogicalExpr(*LogicalExpr) uint64
+	visitMapExpr(*MapExpr) uint64
+	visitSetExpr(*SetExpr) uint64
+	visitSliceExpr(*SliceExpr) uint64
+	visitSuperExpr(*SuperExpr) uint64
+	visitThisExpr(*ThisExpr) uint64
 	visitUnaryExpr(*UnaryExpr) uint64
 	visitVariableExpr(*VariableExpr) uint64
 }
@@ -157,9 +290,19 @@ type visitorExprUint64 interface {
 type visitorExprUintptr interface {
 	visitAssignExpr(*AssignExpr) uintptr
 	visitBinaryExpr(*BinaryExpr) uintptr
+	visitCallExpr(*CallExpr) uintptr
+	visitGetExpr(*GetExpr) uintptr
 	visitGroupingExpr(*GroupingExpr) uintptr
+	visitIndexExpr(*IndexExpr) uintptr
+	visitIndexSetExpr(*IndexSetExpr) uintptr
+	visitListExpr(*ListExpr) uintptr
 	visitLiteralExpr(*LiteralExpr) uintptr
 	visitLogicalExpr(*LogicalExpr) uintptr
+	visitMapExpr(*MapExpr) uintptr
+	visitSetExpr(*SetExpr) uintptr
+	visitSliceExpr(*SliceExpr) uintptr
+	visitSuperExpr(*SuperExpr) uintptr
+	visitThisExpr(*ThisExpr) uintptr
 	visitUnaryExpr(*UnaryExpr) uintptr
 	visitVariableExpr(*VariableExpr) uintptr
 }
@@ -167,9 +310,19 @@ type visitorExprUintptr interface {
 type visitorExprByte interface {
 	visitAssignExpr(*AssignExpr) byte
 	visitBinaryExpr(*BinaryExpr) byte
+	visitCallExpr(*CallExpr) byte
+	visitGetExpr(*GetExpr) byte
 	visitGroupingExpr(*GroupingExpr) byte
+	visitIndexExpr(*IndexExpr) byte
+	visitIndexSetExpr(*IndexSetExpr) byte
+	visitListExpr(*ListExpr) byte
 	visitLiteralExpr(*LiteralExpr) byte
 	visitLogicalExpr(*LogicalExpr) byte
+	visitMapExpr(*MapExpr) byte
+	visitSetExpr(*SetExpr) byte
+	visitSliceExpr(*SliceExpr) byte
+	visitSuperExpr(*SuperExpr) byte
+	visitThisExpr(*ThisExpr) byte
 	visitUnaryExpr(*UnaryExpr) byte
 	visitVariableExpr(*VariableExpr) byte
 }
@@ -177,9 +330,19 @@ type visitorExprByte interface {
 type visitorExprRune interface {
 	visitAssignExpr(*AssignExpr) rune
 	visitBinaryExpr(*BinaryExpr) rune
+	visitCallExpr(*CallExpr) rune
+	visitGetExpr(*GetExpr) rune
 	visitGroupingExpr(*GroupingExpr) rune
+	visitIndexExpr(*IndexExpr) rune
+	visitIndexSetExpr(*IndexSetExpr) rune
+	visitListExpr(*ListExpr) rune
 	visitLiteralExpr(*LiteralExpr) rune
 	visitLogicalExpr(*LogicalExpr) rune
+	visitMapExpr(*MapExpr) rune
+	visitSetExpr(*SetExpr) rune
+	visitSliceExpr(*SliceExpr) rune
+	visitSuperExpr(*SuperExpr) rune
+	visitThisExpr(*ThisExpr) rune
 	visitUnaryExpr(*UnaryExpr) rune
 	visitVariableExpr(*VariableExpr) rune
 }
@@ -187,9 +350,19 @@ type visitorExprRune interface {
 type visitorExprFloat32 interface {
 	visitAssignExpr(*AssignExpr) float32
 	visitBinaryExpr(*BinaryExpr) float32
+	visitCallExpr(*CallExpr) float32
+	visitGetExpr(*GetExpr) float32
 	visitGroupingExpr(*GroupingExpr) float32
+	visitIndexExpr(*IndexExpr) float32
+	visitIndexSetExpr(*IndexSetExpr) float32
+	visitListExpr(*ListExpr) float32
 	visitLiteralExpr(*LiteralExpr) float32
 	visitLogicalExpr(*LogicalExpr) float32
+	visitMapExpr(*MapExpr) float32
+	visitSetExpr(*SetExpr) float32
+	visitSliceExpr(*SliceExpr) float32
+	visitSuperExpr(*SuperExpr) float32
+	visitThisExpr(*ThisExpr) float32
 	visitUnaryExpr(*UnaryExpr) float32
 	visitVariableExpr(*VariableExpr) float32
 }
@@ -197,9 +370,19 @@ type visitorExprFloat32 interface {
 type visitorExprFloat64 interface {
 	visitAssignExpr(*AssignExpr) float64
 	visitBinaryExpr(*BinaryExpr) float64
+	visitCallExpr(*CallExpr) float64
+	visitGetExpr(*GetExpr) float64
 	visitGroupingExpr(*GroupingExpr) float64
+	visitIndexExpr(*IndexExpr) float64
+	visitIndexSetExpr(*IndexSetExpr) float64
+	visitListExpr(*ListExpr) float64
 	visitLiteralExpr(*LiteralExpr) float64
 	visitLogicalExpr(*LogicalExpr) float64
+	visitMapExpr(*MapExpr) float64
+	visitSetExpr(*SetExpr) float64
+	visitSliceExpr(*SliceExpr) float64
+	visitSuperExpr(*SuperExpr) float64
+	visitThisExpr(*ThisExpr) float64
 	visitUnaryExpr(*UnaryExpr) float64
 	visitVariableExpr(*VariableExpr) float64
 }
@@ -207,9 +390,19 @@ type visitorExprFloat64 interface {
 type visitorExprComplex64 interface {
 	visitAssignExpr(*AssignExpr) complex64
 	visitBinaryExpr(*BinaryExpr) complex64
+	visitCallExpr(*CallExpr) complex64
+	visitGetExpr(*GetExpr) complex64
 	visitGroupingExpr(*GroupingExpr) complex64
+	visitIndexExpr(*IndexExpr) complex64
+	visitIndexSetExpr(*IndexSetExpr) complex64
+	visitListExpr(*ListExpr) complex64
 	visitLiteralExpr(*LiteralExpr) complex64
 	visitLogicalExpr(*LogicalExpr) complex64
+	visitMapExpr(*MapExpr) complex64
+	visitSetExpr(*SetExpr) complex64
+	visitSliceExpr(*SliceExpr) complex64
+	visitSuperExpr(*SuperExpr) complex64
+	visitThisExpr(*ThisExpr) complex64
 	visitUnaryExpr(*UnaryExpr) complex64
 	visitVariableExpr(*VariableExpr) complex64
 }
@@ -217,9 +410,19 @@ type visitorExprComplex64 interface {
 type visitorExprComplex128 interface {
 	visitAssignExpr(*AssignExpr) complex128
 	visitBinaryExpr(*BinaryExpr) complex128
+	visitCallExpr(*CallExpr) complex128
+	visitGetExpr(*GetExpr) complex128
 	visitGroupingExpr(*GroupingExpr) complex128
+	visitIndexExpr(*IndexExpr) complex128
+	visitIndexSetExpr(*IndexSetExpr) complex128
+	visitListExpr(*ListExpr) complex128
 	visitLiteralExpr(*LiteralExpr) complex128
 	visitLogicalExpr(*LogicalExpr) complex128
+	visitMapExpr(*MapExpr) complex128
+	visitSetExpr(*SetExpr) complex128
+	visitSliceExpr(*SliceExpr) complex128
+	visitSuperExpr(*SuperExpr) complex128
+	visitThisExpr(*ThisExpr) complex128
 	visitUnaryExpr(*UnaryExpr) complex128
 	visitVariableExpr(*VariableExpr) complex128
 }
@@ -227,6 +430,7 @@ type visitorExprComplex128 interface {
 type AssignExpr struct {
 	name  Token
 	value Expr
+	span  Span
 }
 
 // AssignExpr implements Expr
@@ -239,6 +443,14 @@ func NewAssignExpr(name Token, value Expr) *AssignExpr {
 	}
 }
 
+func (expr *AssignExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *AssignExpr) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *AssignExpr) Accept(v visitorExpr) interface{} {
 	return v.visitAssignExpr(expr)
 }
@@ -323,6 +535,7 @@ type BinaryExpr struct {
 	left     Expr
 	operator Token
 	right    Expr
+	span     Span
 }
 
 // BinaryExpr implements Expr
@@ -336,6 +549,14 @@ func NewBinaryExpr(left Expr, operator Token, right Expr) *BinaryExpr {
 	}
 }
 
+func (expr *BinaryExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *BinaryExpr) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *BinaryExpr) Accept(v visitorExpr) interface{} {
 	return v.visitBinaryExpr(expr)
 }
@@ -416,8 +637,219 @@ func (expr *BinaryExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
 	return v.visitBinaryExpr(expr)
 }
 
+type CallExpr struct {
+	callee    Expr
+	paren     Token
+	arguments []Expr
+	span      Span
+}
+
+// CallExpr implements Expr
+var _ Expr = &CallExpr{}
+
+func NewCallExpr(callee Expr, paren Token, arguments []Expr) *CallExpr {
+	return &CallExpr{
+		callee:    callee,
+		paren:     paren,
+		arguments: arguments,
+	}
+}
+
+func (expr *CallExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *CallExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *CallExpr) Accept(v visitorExpr) interface{} {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptString(v visitorExprString) string {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitCallExpr(expr)
+}
+
+func (expr *CallExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitCallExpr(expr)
+}
+
+type GetExpr struct {
+	object Expr
+	name   Token
+	span   Span
+}
+
+// GetExpr implements Expr
+var _ Expr = &GetExpr{}
+
+func NewGetExpr(object Expr, name Token) *GetExpr {
+	return &GetExpr{
+		object: object,
+		name:   name,
+	}
+}
+
+func (expr *GetExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *GetExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *GetExpr) Accept(v visitorExpr) interface{} {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptString(v visitorExprString) string {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitGetExpr(expr)
+}
+
+func (expr *GetExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitGetExpr(expr)
+}
+
 type GroupingExpr struct {
 	expression Expr
+	span       Span
 }
 
 // GroupingExpr implements Expr
@@ -429,6 +861,14 @@ func NewGroupingExpr(expression Expr) *GroupingExpr {
 	}
 }
 
+func (expr *GroupingExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *GroupingExpr) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *GroupingExpr) Accept(v visitorExpr) interface{} {
 	return v.visitGroupingExpr(expr)
 }
@@ -509,268 +949,1139 @@ func (expr *GroupingExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
 	return v.visitGroupingExpr(expr)
 }
 
-type LiteralExpr struct {
-	value interface{}
+type IndexExpr struct {
+	object  Expr
+	bracket Token
+	index   Expr
+	span    Span
 }
 
-// LiteralExpr implements Expr
-var _ Expr = &LiteralExpr{}
+// IndexExpr implements Expr
+var _ Expr = &IndexExpr{}
 
-func NewLiteralExpr(value interface{}) *LiteralExpr {
-	return &LiteralExpr{
-		value: value,
+func NewIndexExpr(object Expr, bracket Token, index Expr) *IndexExpr {
+	return &IndexExpr{
+		object:  object,
+		bracket: bracket,
+		index:   index,
 	}
 }
 
-func (expr *LiteralExpr) Accept(v visitorExpr) interface{} {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) Span() Span {
+	return expr.span
 }
 
-func (expr *LiteralExpr) AcceptBool(v visitorExprBool) bool {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) setSpan(span Span) {
+	expr.span = span
 }
 
-func (expr *LiteralExpr) AcceptString(v visitorExprString) string {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) Accept(v visitorExpr) interface{} {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptInt(v visitorExprInt) int {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptInt8(v visitorExprInt8) int8 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptString(v visitorExprString) string {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptInt16(v visitorExprInt16) int16 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptInt32(v visitorExprInt32) int32 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptInt64(v visitorExprInt64) int64 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptUint(v visitorExprUint) uint {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptUint8(v visitorExprUint8) uint8 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptUint16(v visitorExprUint16) uint16 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptUint32(v visitorExprUint32) uint32 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptUint64(v visitorExprUint64) uint64 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptByte(v visitorExprByte) byte {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptRune(v visitorExprRune) rune {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptFloat32(v visitorExprFloat32) float32 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptFloat64(v visitorExprFloat64) float64 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitIndexExpr(expr)
 }
 
-func (expr *LiteralExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
-	return v.visitLiteralExpr(expr)
+func (expr *IndexExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitIndexExpr(expr)
 }
 
-type LogicalExpr struct {
-	left     Expr
-	operator Token
-	right    Expr
+func (expr *IndexExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitIndexExpr(expr)
 }
 
-// LogicalExpr implements Expr
-var _ Expr = &LogicalExpr{}
+func (expr *IndexExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitIndexExpr(expr)
+}
 
-func NewLogicalExpr(left Expr, operator Token, right Expr) *LogicalExpr {
-	return &LogicalExpr{
-		left:     left,
-		operator: operator,
-		right:    right,
+type IndexSetExpr struct {
+	object  Expr
+	bracket Token
+	index   Expr
+	value   Expr
+	span    Span
+}
+
+// IndexSetExpr implements Expr
+var _ Expr = &IndexSetExpr{}
+
+func NewIndexSetExpr(object Expr, bracket Token, index Expr, value Expr) *IndexSetExpr {
+	return &IndexSetExpr{
+		object:  object,
+		bracket: bracket,
+		index:   index,
+		value:   value,
 	}
 }
 
-func (expr *LogicalExpr) Accept(v visitorExpr) interface{} {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) Span() Span {
+	return expr.span
 }
 
-func (expr *LogicalExpr) AcceptBool(v visitorExprBool) bool {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) setSpan(span Span) {
+	expr.span = span
 }
 
-func (expr *LogicalExpr) AcceptString(v visitorExprString) string {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) Accept(v visitorExpr) interface{} {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptInt(v visitorExprInt) int {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptInt8(v visitorExprInt8) int8 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptString(v visitorExprString) string {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptInt16(v visitorExprInt16) int16 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptInt32(v visitorExprInt32) int32 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptInt64(v visitorExprInt64) int64 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptUint(v visitorExprUint) uint {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptUint8(v visitorExprUint8) uint8 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptUint16(v visitorExprUint16) uint16 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptUint32(v visitorExprUint32) uint32 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptUint64(v visitorExprUint64) uint64 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptByte(v visitorExprByte) byte {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptRune(v visitorExprRune) rune {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptFloat32(v visitorExprFloat32) float32 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptFloat64(v visitorExprFloat64) float64 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitIndexSetExpr(expr)
 }
 
-func (expr *LogicalExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
-	return v.visitLogicalExpr(expr)
+func (expr *IndexSetExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitIndexSetExpr(expr)
 }
 
-type UnaryExpr struct {
-	operator Token
-	right    Expr
+func (expr *IndexSetExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitIndexSetExpr(expr)
 }
 
-// UnaryExpr implements Expr
-var _ Expr = &UnaryExpr{}
+func (expr *IndexSetExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitIndexSetExpr(expr)
+}
 
-func NewUnaryExpr(operator Token, right Expr) *UnaryExpr {
-	return &UnaryExpr{
-		operator: operator,
-		right:    right,
+type ListExpr struct {
+	bracket  Token
+	elements []Expr
+	span     Span
+}
+
+// ListExpr implements Expr
+var _ Expr = &ListExpr{}
+
+func NewListExpr(bracket Token, elements []Expr) *ListExpr {
+	return &ListExpr{
+		bracket:  bracket,
+		elements: elements,
 	}
 }
 
-func (expr *UnaryExpr) Accept(v visitorExpr) interface{} {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) Span() Span {
+	return expr.span
 }
 
-func (expr *UnaryExpr) AcceptBool(v visitorExprBool) bool {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) setSpan(span Span) {
+	expr.span = span
 }
 
-func (expr *UnaryExpr) AcceptString(v visitorExprString) string {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) Accept(v visitorExpr) interface{} {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptInt(v visitorExprInt) int {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptInt8(v visitorExprInt8) int8 {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptString(v visitorExprString) string {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptInt16(v visitorExprInt16) int16 {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptInt32(v visitorExprInt32) int32 {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptInt64(v visitorExprInt64) int64 {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptUint(v visitorExprUint) uint {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptUint8(v visitorExprUint8) uint8 {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptUint16(v visitorExprUint16) uint16 {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptUint32(v visitorExprUint32) uint32 {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptUint64(v visitorExprUint64) uint64 {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
-	return v.visitUnaryExpr(expr)
+func (expr *ListExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitListExpr(expr)
 }
 
-func (expr *UnaryExpr) AcceptByte(v visitorExprByte) byte {
+func (expr *ListExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitListExpr(expr)
+}
+
+func (expr *ListExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitListExpr(expr)
+}
+
+func (expr *ListExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitListExpr(expr)
+}
+
+func (expr *ListExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitListExpr(expr)
+}
+
+func (expr *ListExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitListExpr(expr)
+}
+
+func (expr *ListExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitListExpr(expr)
+}
+
+func (expr *ListExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitListExpr(expr)
+}
+
+func (expr *ListExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitListExpr(expr)
+}
+
+type LiteralExpr struct {
+	value interface{}
+	span  Span
+}
+
+// LiteralExpr implements Expr
+var _ Expr = &LiteralExpr{}
+
+func NewLiteralExpr(value interface{}) *LiteralExpr {
+	return &LiteralExpr{
+		value: value,
+	}
+}
+
+func (expr *LiteralExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *LiteralExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *LiteralExpr) Accept(v visitorExpr) interface{} {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptString(v visitorExprString) string {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitLiteralExpr(expr)
+}
+
+func (expr *LiteralExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitLiteralExpr(expr)
+}
+
+type LogicalExpr struct {
+	left     Expr
+	operator Token
+	right    Expr
+	span     Span
+}
+
+// LogicalExpr implements Expr
+var _ Expr = &LogicalExpr{}
+
+func NewLogicalExpr(left Expr, operator Token, right Expr) *LogicalExpr {
+	return &LogicalExpr{
+		left:     left,
+		operator: operator,
+		right:    right,
+	}
+}
+
+func (expr *LogicalExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *LogicalExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *LogicalExpr) Accept(v visitorExpr) interface{} {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptString(v visitorExprString) string {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitLogicalExpr(expr)
+}
+
+func (expr *LogicalExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitLogicalExpr(expr)
+}
+
+type MapExpr struct {
+	brace  Token
+	keys   []Expr
+	values []Expr
+	span   Span
+}
+
+// MapExpr implements Expr
+var _ Expr = &MapExpr{}
+
+func NewMapExpr(brace Token, keys []Expr, values []Expr) *MapExpr {
+	return &MapExpr{
+		brace:  brace,
+		keys:   keys,
+		values: values,
+	}
+}
+
+func (expr *MapExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *MapExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *MapExpr) Accept(v visitorExpr) interface{} {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptString(v visitorExprString) string {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitMapExpr(expr)
+}
+
+func (expr *MapExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitMapExpr(expr)
+}
+
+type SetExpr struct {
+	object Expr
+	name   Token
+	value  Expr
+	span   Span
+}
+
+// SetExpr implements Expr
+var _ Expr = &SetExpr{}
+
+func NewSetExpr(object Expr, name Token, value Expr) *SetExpr {
+	return &SetExpr{
+		object: object,
+		name:   name,
+		value:  value,
+	}
+}
+
+func (expr *SetExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *SetExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *SetExpr) Accept(v visitorExpr) interface{} {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptString(v visitorExprString) string {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitSetExpr(expr)
+}
+
+func (expr *SetExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitSetExpr(expr)
+}
+
+type SliceExpr struct {
+	object  Expr
+	bracket Token
+	start   Expr
+	end     Expr
+	span    Span
+}
+
+// SliceExpr implements Expr
+var _ Expr = &SliceExpr{}
+
+func NewSliceExpr(object Expr, bracket Token, start Expr, end Expr) *SliceExpr {
+	return &SliceExpr{
+		object:  object,
+		bracket: bracket,
+		start:   start,
+		end:     end,
+	}
+}
+
+func (expr *SliceExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *SliceExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *SliceExpr) Accept(v visitorExpr) interface{} {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptString(v visitorExprString) string {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitSliceExpr(expr)
+}
+
+func (expr *SliceExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitSliceExpr(expr)
+}
+
+type SuperExpr struct {
+	keyword Token
+	method  Token
+	span    Span
+}
+
+// SuperExpr implements Expr
+var _ Expr = &SuperExpr{}
+
+func NewSuperExpr(keyword Token, method Token) *SuperExpr {
+	return &SuperExpr{
+		keyword: keyword,
+		method:  method,
+	}
+}
+
+func (expr *SuperExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *SuperExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *SuperExpr) Accept(v visitorExpr) interface{} {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptString(v visitorExprString) string {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitSuperExpr(expr)
+}
+
+func (expr *SuperExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitSuperExpr(expr)
+}
+
+type ThisExpr struct {
+	keyword Token
+	span    Span
+}
+
+// ThisExpr implements Expr
+var _ Expr = &ThisExpr{}
+
+func NewThisExpr(keyword Token) *ThisExpr {
+	return &ThisExpr{
+		keyword: keyword,
+	}
+}
+
+func (expr *ThisExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *ThisExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *ThisExpr) Accept(v visitorExpr) interface{} {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptString(v visitorExprString) string {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptByte(v visitorExprByte) byte {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptRune(v visitorExprRune) rune {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptFloat32(v visitorExprFloat32) float32 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptFloat64(v visitorExprFloat64) float64 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptComplex64(v visitorExprComplex64) complex64 {
+	return v.visitThisExpr(expr)
+}
+
+func (expr *ThisExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
+	return v.visitThisExpr(expr)
+}
+
+type UnaryExpr struct {
+	operator Token
+	right    Expr
+	span     Span
+}
+
+// UnaryExpr implements Expr
+var _ Expr = &UnaryExpr{}
+
+func NewUnaryExpr(operator Token, right Expr) *UnaryExpr {
+	return &UnaryExpr{
+		operator: operator,
+		right:    right,
+	}
+}
+
+func (expr *UnaryExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *UnaryExpr) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *UnaryExpr) Accept(v visitorExpr) interface{} {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptBool(v visitorExprBool) bool {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptString(v visitorExprString) string {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptInt(v visitorExprInt) int {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptInt8(v visitorExprInt8) int8 {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptInt16(v visitorExprInt16) int16 {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptInt32(v visitorExprInt32) int32 {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptInt64(v visitorExprInt64) int64 {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptUint(v visitorExprUint) uint {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptUint8(v visitorExprUint8) uint8 {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptUint16(v visitorExprUint16) uint16 {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptUint32(v visitorExprUint32) uint32 {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptUint64(v visitorExprUint64) uint64 {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptUintptr(v visitorExprUintptr) uintptr {
+	return v.visitUnaryExpr(expr)
+}
+
+func (expr *UnaryExpr) AcceptByte(v visitorExprByte) byte {
 	return v.visitUnaryExpr(expr)
 }
 
@@ -796,6 +2107,7 @@ func (expr *UnaryExpr) AcceptComplex128(v visitorExprComplex128) complex128 {
 
 type VariableExpr struct {
 	name Token
+	span Span
 }
 
 // VariableExpr implements Expr
@@ -807,6 +2119,14 @@ func NewVariableExpr(name Token) *VariableExpr {
 	}
 }
 
+func (expr *VariableExpr) Span() Span {
+	return expr.span
+}
+
+func (expr *VariableExpr) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *VariableExpr) Accept(v visitorExpr) interface{} {
 	return v.visitVariableExpr(expr)
 }
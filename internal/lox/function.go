@@ -0,0 +1,111 @@
+package lox
+
+import (
+	"fmt"
+	"time"
+)
+
+type loxFunction struct {
+	declaration *FunctionStmt
+	// closure is the environment the function was declared in. It is kept alive as long as the function
+	// is so that free variables resolve against it rather than against the caller's environment.
+	closure *environment
+	// isInitializer is true for the "init" method of a class, which always returns the instance.
+	isInitializer bool
+}
+
+// loxFunction implements LoxCallable
+var _ LoxCallable = &loxFunction{}
+
+func newLoxFunction(declaration *FunctionStmt, closure *environment, isInitializer bool) *loxFunction {
+	return &loxFunction{
+		declaration:   declaration,
+		closure:       closure,
+		isInitializer: isInitializer,
+	}
+}
+
+// bind returns a copy of the method whose closure defines "this" as the given instance.
+func (f *loxFunction) bind(instance *loxInstance) *loxFunction {
+	env := newScopedEnvironment(f.closure)
+	env.define("this", instance)
+	return newLoxFunction(f.declaration, env, f.isInitializer)
+}
+
+func (f *loxFunction) arity() int {
+	return len(f.declaration.params)
+}
+
+func (f *loxFunction) call(i *interpreter, paren Token, arguments []interface{}) interface{} {
+	if err := i.allocate(paren, environmentSize+len(arguments)*entrySize); err != nil {
+		return err
+	}
+	env := newScopedEnvironment(f.closure)
+	for idx, param := range f.declaration.params {
+		env.define(param.Lexeme, arguments[idx])
+	}
+	result := i.executeBlock(f.declaration.body, env)
+	err, ok := result.(*RuntimeError)
+	if ok {
+		return err
+	}
+	if f.isInitializer {
+		return f.closure.getAt(0, "this")
+	}
+	if result, ok := result.(*returnValue); ok {
+		return result.value
+	}
+	return nil
+}
+
+func (f *loxFunction) String() string {
+	return fmt.Sprintf("<fn %s>", f.declaration.name.Lexeme)
+}
+
+// NativeFunction is a function implemented in Go and callable from lox code. It returns either the result
+// of the call or an error, which becomes a runtime error at the call.
+type NativeFunction func(arguments []interface{}) (interface{}, error)
+
+// nativeFunction is a function implemented in Go and exposed to lox code.
+type nativeFunction struct {
+	name       string
+	arityValue int
+	function   NativeFunction
+}
+
+// nativeFunction implements LoxCallable
+var _ LoxCallable = &nativeFunction{}
+
+func (f *nativeFunction) arity() int {
+	return f.arityValue
+}
+
+func (f *nativeFunction) call(i *interpreter, paren Token, arguments []interface{}) interface{} {
+	result, err := f.function(arguments)
+	if err != nil {
+		if err, ok := err.(*RuntimeError); ok {
+			return err
+		}
+		return &RuntimeError{Token: paren, Message: err.Error()}
+	}
+	return result
+}
+
+func (f *nativeFunction) String() string {
+	return "<native fn>"
+}
+
+// defineNatives adds the functions of the lox standard library to the globals of the interpreter.
+func defineNatives(i *interpreter) {
+	defineListNatives(i)
+	defineMapNatives(i)
+	defineRangeNatives(i)
+	defineErrorClass(i)
+	i.globals.define("clock", &nativeFunction{
+		name:       "clock",
+		arityValue: 0,
+		function: func(arguments []interface{}) (interface{}, error) {
+			return float64(time.Now().UnixNano()) / float64(time.Second), nil
+		},
+	})
+}
@@ -0,0 +1,259 @@
+package lox
+
+import (
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+const (
+	// defaultGCHeapGrowFactor is how much the heap can grow after a collection before the next one.
+	defaultGCHeapGrowFactor = 2
+	// defaultGCInitialHeap is the number of bytes allocated before the first collection.
+	defaultGCInitialHeap = 1024 * 1024
+)
+
+// estimated sizes of the data referenced by objects, used to account for the memory they hold
+var (
+	valueSize    = int(unsafe.Sizeof(value{}))
+	mapEntrySize = int(unsafe.Sizeof("")) + valueSize
+)
+
+// VMOption configures a virtual machine created with NewVM.
+type VMOption func(vm *vm)
+
+// WithGCHeapGrowFactor sets how much the heap can grow after a collection before the next collection is
+// triggered. It must be greater than 1.
+func WithGCHeapGrowFactor(factor float64) VMOption {
+	return func(vm *vm) {
+		vm.gcHeapGrowFactor = factor
+	}
+}
+
+// WithGCInitialHeap sets the number of bytes that can be allocated before the first collection.
+func WithGCInitialHeap(bytes int) VMOption {
+	return func(vm *vm) {
+		vm.nextGC = bytes
+	}
+}
+
+// WithStressGC makes the virtual machine collect garbage before every allocation. It is slow and meant
+// to find objects that are not reachable from the roots while they are still in use.
+func WithStressGC() VMOption {
+	return func(vm *vm) {
+		vm.stressGC = true
+	}
+}
+
+// WithGCLog makes the virtual machine log each collection to w.
+func WithGCLog(w io.Writer) VMOption {
+	return func(vm *vm) {
+		vm.gcLog = w
+	}
+}
+
+// GCStats describes the state of the heap of a virtual machine.
+type GCStats struct {
+	// Collections is the number of garbage collections run so far.
+	Collections int
+	// Objects is the number of live objects allocated by the virtual machine, plus the garbage not
+	// collected yet.
+	Objects int
+	// BytesAllocated is the estimated size of these objects.
+	BytesAllocated int
+	// BytesFreed is the estimated size of all the objects collected so far.
+	BytesFreed int
+	// NextGC is the heap size that triggers the next collection.
+	NextGC int
+}
+
+func (vm *vm) GCStats() GCStats {
+	stats := vm.gcStats
+	stats.BytesAllocated = vm.bytesAllocated
+	stats.NextGC = vm.nextGC
+	stats.Objects = 0
+	for o := vm.objects; o != nil; o = o.header().nextObject {
+		stats.Objects++
+	}
+	return stats
+}
+
+// allocate registers an object created by the virtual machine so that the garbage collector can free it.
+// A collection may run before the object is registered, so anything the new object references must be
+// reachable from the roots when allocate is called.
+func (vm *vm) allocate(o obj) {
+	size := objectSize(o)
+	vm.bytesAllocated += size
+	if vm.stressGC || vm.bytesAllocated > vm.nextGC {
+		vm.collectGarbage()
+	}
+	o.header().nextObject = vm.objects
+	vm.objects = o
+}
+
+// grow accounts for memory an object acquires after it was allocated, such as a new field.
+func (vm *vm) grow(bytes int) {
+	vm.bytesAllocated += bytes
+}
+
+// newString returns the interned string with the given characters, allocating it if needed.
+func (vm *vm) newString(chars string) *objString {
+	if interned, ok := vm.strings[chars]; ok {
+		return interned
+	}
+	s := &objString{chars: chars}
+	vm.allocate(s)
+	vm.strings[chars] = s
+	return s
+}
+
+func objectSize(o obj) int {
+	switch o := o.(type) {
+	case *objString:
+		return int(unsafe.Sizeof(*o)) + len(o.chars)
+	case *objNative:
+		return int(unsafe.Sizeof(*o)) + len(o.name)
+	case *objUpvalue:
+		return int(unsafe.Sizeof(*o))
+	case *objClosure:
+		return int(unsafe.Sizeof(*o)) + len(o.upvalues)*int(unsafe.Sizeof(o))
+	case *objClass:
+		return int(unsafe.Sizeof(*o)) + len(o.name) + len(o.methods)*mapEntrySize
+	case *objInstance:
+		return int(unsafe.Sizeof(*o)) + len(o.fields)*mapEntrySize
+	case *objBoundMethod:
+		return int(unsafe.Sizeof(*o))
+	}
+	return 0
+}
+
+// collectGarbage runs a tri-color mark-and-sweep collection. Objects reachable from the roots are marked
+// gray, then blackened by marking everything they reference, and the objects left white are freed.
+func (vm *vm) collectGarbage() {
+	before := vm.bytesAllocated
+	vm.markRoots()
+	vm.traceReferences()
+	vm.removeWhiteStrings()
+	vm.sweep()
+	vm.nextGC = int(float64(vm.bytesAllocated) * vm.gcHeapGrowFactor)
+	if vm.nextGC < defaultGCInitialHeap && !vm.stressGC {
+		vm.nextGC = defaultGCInitialHeap
+	}
+	vm.gcStats.Collections++
+	vm.gcStats.BytesFreed += before - vm.bytesAllocated
+	if vm.gcLog != nil {
+		fmt.Fprintf(vm.gcLog, "-- gc collected %d bytes (from %d to %d) next at %d\n",
+			before-vm.bytesAllocated, before, vm.bytesAllocated, vm.nextGC)
+	}
+}
+
+func (vm *vm) markRoots() {
+	for _, v := range vm.stack {
+		vm.markValue(v)
+	}
+	for _, frame := range vm.frames {
+		vm.markObject(frame.closure)
+	}
+	for upvalue := vm.openUpvalues; upvalue != nil; upvalue = upvalue.next {
+		vm.markObject(upvalue)
+	}
+	for _, v := range vm.globals {
+		vm.markValue(v)
+	}
+}
+
+func (vm *vm) markValue(v value) {
+	if v.typ == valueObj {
+		vm.markObject(v.obj)
+	}
+}
+
+// markObject marks an object gray. Objects created by the compiler are marked too, since reaching the
+// constants of a function is the only way to reach the strings and functions nested in it. They are not
+// in the list of objects so their mark is never cleared, which is harmless: constants only reference other
+// constants, so there is never anything new to mark through them.
+func (vm *vm) markObject(o obj) {
+	h := o.header()
+	if h.isMarked {
+		return
+	}
+	h.isMarked = true
+	vm.grayStack = append(vm.grayStack, o)
+}
+
+func (vm *vm) traceReferences() {
+	for len(vm.grayStack) > 0 {
+		o := vm.grayStack[len(vm.grayStack)-1]
+		vm.grayStack = vm.grayStack[:len(vm.grayStack)-1]
+		vm.blackenObject(o)
+	}
+}
+
+// blackenObject marks every object referenced by o.
+func (vm *vm) blackenObject(o obj) {
+	switch o := o.(type) {
+	case *objUpvalue:
+		vm.markValue(o.closed)
+	case *objFunction:
+		for _, constant := range o.chunk.constants {
+			vm.markValue(constant)
+		}
+	case *objClosure:
+		vm.markObject(o.function)
+		for _, upvalue := range o.upvalues {
+			if upvalue != nil {
+				vm.markObject(upvalue)
+			}
+		}
+	case *objClass:
+		for _, method := range o.methods {
+			vm.markObject(method)
+		}
+	case *objInstance:
+		vm.markObject(o.class)
+		for _, field := range o.fields {
+			vm.markValue(field)
+		}
+	case *objBoundMethod:
+		vm.markValue(o.receiver)
+		vm.markObject(o.method)
+	}
+}
+
+// removeWhiteStrings drops the unmarked strings from the intern table, which only holds weak references.
+func (vm *vm) removeWhiteStrings() {
+	for chars, s := range vm.strings {
+		if !s.isMarked {
+			delete(vm.strings, chars)
+		}
+	}
+}
+
+// sweep frees the unmarked objects by unlinking them from the list of objects, and clears the marks of
+// the others for the next collection.
+func (vm *vm) sweep() {
+	var previous obj = nil
+	o := vm.objects
+	for o != nil {
+		h := o.header()
+		if h.isMarked {
+			h.isMarked = false
+			previous = o
+			o = h.nextObject
+			continue
+		}
+		unreached := o
+		o = h.nextObject
+		if previous == nil {
+			vm.objects = o
+		} else {
+			previous.header().nextObject = o
+		}
+		vm.free(unreached)
+	}
+}
+
+func (vm *vm) free(o obj) {
+	vm.bytesAllocated -= objectSize(o)
+	o.header().nextObject = nil
+}
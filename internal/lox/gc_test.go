@@ -0,0 +1,77 @@
+package lox
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCCollectsGarbage(t *testing.T) {
+	source := `
+class Node {
+  init(value, next) {
+    this.value = value;
+    this.next = next;
+  }
+}
+var kept = nil;
+for (var i = 0; i < 2000; i = i + 1) {
+  var garbage = Node("garbage " + "string", nil);
+  if (i < 10) kept = Node(i, kept);
+}
+var sum = 0;
+while (kept != nil) {
+  sum = sum + kept.value;
+  kept = kept.next;
+}
+print sum;`
+	statements, _ := resolve(t, source)
+	function := NewCompiler().Compile(statements)
+	var log bytes.Buffer
+	var output bytes.Buffer
+	vm := NewVM(WithGCInitialHeap(4096), WithGCHeapGrowFactor(1.5), WithGCLog(&log), WithVMOutput(&output))
+	require.NoError(t, vm.Interpret(function))
+	assert.Equal(t, "45\n", output.String())
+
+	stats := vm.GCStats()
+	assert.Greater(t, stats.Collections, 0)
+	assert.Greater(t, stats.BytesFreed, 0)
+	assert.Contains(t, log.String(), "-- gc collected")
+	vm.collectGarbage()
+	// only the 10 kept nodes, the Node class, the native clock and the interned strings can survive
+	assert.Less(t, vm.GCStats().Objects, 100)
+	assert.LessOrEqual(t, len(vm.strings), 1)
+}
+
+func TestGCKeepsReachableObjects(t *testing.T) {
+	source := `
+fun makeCounter() {
+  var count = 0;
+  fun increment() {
+    count = count + 1;
+    return "count " + "is";
+  }
+  fun get() {
+    return count;
+  }
+  class Counter {
+    increment() {
+      return increment();
+    }
+    get() {
+      return get();
+    }
+  }
+  return Counter();
+}
+var counter = makeCounter();
+for (var i = 0; i < 100; i = i + 1) {
+  counter.increment();
+}
+var bound = counter.get;
+print bound();`
+	output := runVM(t, source, WithStressGC())
+	require.Equal(t, "100\n", output)
+}
@@ -0,0 +1,70 @@
+package lox
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+// TestGolden runs the scripts of testdata on both backends. What they print must match the .stdout golden
+// file, and the errors they report the .interpreter.stderr and .vm.stderr golden files, which are absent
+// when no error is expected. Run the tests with -update to write the golden files.
+func TestGolden(t *testing.T) {
+	scripts, err := filepath.Glob(filepath.Join("testdata", "*.lox"))
+	require.NoError(t, err)
+	require.NotEmpty(t, scripts)
+	for _, script := range scripts {
+		name := strings.TrimSuffix(script, ".lox")
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			source, err := ioutil.ReadFile(script)
+			require.NoError(t, err)
+
+			var stdout, stderr bytes.Buffer
+			interpreter := NewInterpreter(WithOutput(&stdout), WithErrorOutput(&stderr))
+			_ = interpreter.Run(script, string(source))
+			checkGolden(t, name+".stdout", stdout.String())
+			checkGolden(t, name+".interpreter.stderr", stderr.String())
+
+			stdout.Reset()
+			stderr.Reset()
+			function, err := Compile(script, string(source))
+			require.NoError(t, err)
+			_ = NewVM(WithVMOutput(&stdout), WithVMErrorOutput(&stderr)).Interpret(function)
+			checkGolden(t, name+".stdout", stdout.String())
+			checkGolden(t, name+".vm.stderr", stderr.String())
+		})
+	}
+}
+
+// checkGolden compares actual to the content of a golden file, or writes it with -update. A missing golden
+// file stands for an empty output.
+func checkGolden(t *testing.T, path, actual string) {
+	t.Helper()
+	if *update {
+		if actual == "" {
+			err := os.Remove(path)
+			if !os.IsNotExist(err) {
+				require.NoError(t, err)
+			}
+			return
+		}
+		require.NoError(t, ioutil.WriteFile(path, []byte(actual), 0644))
+		return
+	}
+	expected, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		assert.Empty(t, actual, path)
+		return
+	}
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), actual, path)
+}
@@ -0,0 +1,143 @@
+package lox
+
+// Kind is the type of a value of the interpreter, as seen by programs embedding it.
+type Kind int
+
+const (
+	KindNil Kind = iota
+	KindBool
+	KindNumber
+	KindString
+	KindFunction
+	KindClass
+	KindInstance
+	KindList
+	KindMap
+	KindRange
+	// KindObject is the kind of the values of other types, which the program embedding the interpreter can
+	// pass to it.
+	KindObject
+)
+
+var kindNames = [...]string{
+	KindNil:      "nil",
+	KindBool:     "boolean",
+	KindNumber:   "number",
+	KindString:   "string",
+	KindFunction: "function",
+	KindClass:    "class",
+	KindInstance: "instance",
+	KindList:     "list",
+	KindMap:      "map",
+	KindRange:    "range",
+	KindObject:   "object",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// KindOf returns the kind of a value of the interpreter.
+func KindOf(value interface{}) Kind {
+	switch value.(type) {
+	case nil:
+		return KindNil
+	case bool:
+		return KindBool
+	case float64:
+		return KindNumber
+	case string:
+		return KindString
+	case *loxClass:
+		return KindClass
+	case *loxInstance, HostObject:
+		return KindInstance
+	case *loxList:
+		return KindList
+	case *loxMap:
+		return KindMap
+	case loxRange:
+		return KindRange
+	case LoxCallable:
+		return KindFunction
+	}
+	return KindObject
+}
+
+// HostObject is a value of the program embedding the interpreter whose properties lox code can get and set,
+// like the fields of an instance. Errors returned by its methods become runtime errors at the property.
+type HostObject interface {
+	Get(name string) (interface{}, error)
+	Set(name string, value interface{}) error
+}
+
+// NewList returns a list value holding elements.
+func NewList(elements []interface{}) interface{} {
+	return newLoxList(elements)
+}
+
+// ListElements returns the elements of a list value, or false if the value is not a list.
+func ListElements(value interface{}) ([]interface{}, bool) {
+	list, ok := value.(*loxList)
+	if !ok {
+		return nil, false
+	}
+	// the elements are copied so that the caller can't change the list
+	elements := make([]interface{}, len(list.elements))
+	copy(elements, list.elements)
+	return elements, true
+}
+
+// NewMap returns a map value holding the given keys, in order, and their values.
+func NewMap(keys, values []interface{}) interface{} {
+	m := newLoxMap()
+	for idx, key := range keys {
+		m.set(key, values[idx])
+	}
+	return m
+}
+
+// MapEntries returns the keys of a map value in order and their values, or false if the value is not a map.
+func MapEntries(value interface{}) ([]interface{}, []interface{}, bool) {
+	m, ok := value.(*loxMap)
+	if !ok {
+		return nil, nil, false
+	}
+	// the keys are copied so that the caller can't change the order of the map
+	keys := make([]interface{}, len(m.keys))
+	copy(keys, m.keys)
+	values := make([]interface{}, 0, len(m.keys))
+	for _, key := range m.keys {
+		values = append(values, m.entries[key])
+	}
+	return keys, values, true
+}
+
+// InstanceFields returns the fields of an instance of a lox class, or false if the value is not one.
+func InstanceFields(value interface{}) (map[string]interface{}, bool) {
+	instance, ok := value.(*loxInstance)
+	if !ok {
+		return nil, false
+	}
+	// the fields are copied so that the caller can't change the instance
+	fields := make(map[string]interface{}, len(instance.fields))
+	for name, field := range instance.fields {
+		fields[name] = field
+	}
+	return fields, true
+}
+
+// Stringify returns the representation of a value written by print statements.
+func Stringify(value interface{}) string {
+	return stringify(value)
+}
+
+// IsTruthy reports whether a value is true when used as a condition.
+func IsTruthy(value interface{}) bool {
+	return isTruthy(value)
+}
+
+// IsEqual reports whether two values are equal with lox semantics.
+func IsEqual(a, b interface{}) bool {
+	return isEqual(a, b)
+}
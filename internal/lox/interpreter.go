@@ -1,32 +1,240 @@
 package lox
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unsafe"
+)
 
 type interpreter struct {
-	env *environment
+	globals *environment
+	env     *environment
+	// locals maps each resolved variable expression to the number of scopes between the scope where it is
+	// used and the scope where it is declared. Expressions that are not in the map refer to globals.
+	locals map[Expr]int
+	// output is where print statements write.
+	output io.Writer
+	// errorOutput is where errors are reported. When it is nil, errors are only returned.
+	errorOutput io.Writer
+
+	// stepLimit is the number of statements a run can execute, or 0 for no limit. steps counts the
+	// statements executed by the current run.
+	stepLimit int
+	steps     int
+	// maxCallDepth is the number of nested calls allowed, or 0 for no limit. frames are the calls in
+	// progress, innermost last, which also make the stack traces of errors.
+	maxCallDepth int
+	frames       []interpreterFrame
+	// errorClass is the class of the error objects caught by catch clauses.
+	errorClass *loxClass
+	// memoryLimit is the number of bytes a run can allocate, or 0 for no limit. allocated counts the
+	// bytes allocated by the current run, as estimated by the sizes below.
+	memoryLimit int
+	allocated   int
+	// ctx is the context of the current run, checked in loops and calls to stop runaway scripts.
+	ctx context.Context
+}
+
+// estimated sizes of the values allocated by the interpreter, used to enforce the memory limit
+var (
+	stringSize      = int(unsafe.Sizeof(""))
+	entrySize       = int(unsafe.Sizeof("")) + int(unsafe.Sizeof(interface{}(nil)))
+	environmentSize = int(unsafe.Sizeof(environment{})) + mapSize
+	instanceSize    = int(unsafe.Sizeof(loxInstance{})) + mapSize
+	functionSize    = int(unsafe.Sizeof(loxFunction{}))
+	listSize        = int(unsafe.Sizeof(loxList{}))
+	elementSize     = int(unsafe.Sizeof(interface{}(nil)))
+	loxMapSize      = int(unsafe.Sizeof(loxMap{})) + mapSize
+	// a map entry holds its key and value in the Go map, and its key again in the ordered keys
+	loxMapEntrySize = 3 * int(unsafe.Sizeof(interface{}(nil)))
+	// mapSize is the size of an empty Go map
+	mapSize = 48
+)
+
+// defaultMaxCallDepth stops infinite recursions well before they exhaust the Go stack.
+const defaultMaxCallDepth = 10000
+
+// Interpreter is the tree-walk interpreter, for programs embedding it.
+type Interpreter = interpreter
+
+// InterpreterOption configures an interpreter.
+type InterpreterOption func(*interpreter)
+
+// WithOutput sets where print statements write. It defaults to stdout.
+func WithOutput(w io.Writer) InterpreterOption {
+	return func(i *interpreter) {
+		i.output = w
+	}
+}
+
+// WithErrorOutput sets where runtime errors, and the static errors found by Run and Eval, are reported. By
+// default, errors are only returned.
+func WithErrorOutput(w io.Writer) InterpreterOption {
+	return func(i *interpreter) {
+		i.errorOutput = w
+	}
+}
+
+// WithStepLimit limits the number of statements a run can execute. A run exceeding it stops with a runtime
+// error wrapping ErrStepLimit. By default, the number of statements is not limited.
+func WithStepLimit(steps int) InterpreterOption {
+	return func(i *interpreter) {
+		i.stepLimit = steps
+	}
+}
+
+// WithMaxCallDepth limits the number of nested calls. A call exceeding it stops the run with a runtime error
+// wrapping ErrStackOverflow. It defaults to 10000, and 0 removes the limit.
+func WithMaxCallDepth(depth int) InterpreterOption {
+	return func(i *interpreter) {
+		i.maxCallDepth = depth
+	}
+}
+
+// WithMemoryLimit limits the number of bytes a run can allocate for strings, environments, instances and
+// collections. A run exceeding it stops with a runtime error wrapping ErrMemoryLimit. The limit applies to
+// the total allocated by a run, not to the memory in use at a given time, and sizes are estimates. By
+// default, memory is not limited.
+func WithMemoryLimit(bytes int) InterpreterOption {
+	return func(i *interpreter) {
+		i.memoryLimit = bytes
+	}
 }
 
 // interpreter implements visitorExpr and visitorStmt
 var _ visitorExpr = &interpreter{}
 var _ visitorStmt = &interpreter{}
 
-func NewInterpreter() *interpreter {
-	return &interpreter{env: newEnvironment()}
+// LoxCallable is a lox value that can be called, such as a function.
+type LoxCallable interface {
+	// arity is the number of arguments the callable expects, or Variadic if it accepts any number.
+	arity() int
+	// call returns either the result of the call or a runtime error. paren is the token closing the
+	// arguments of the call, where errors raised by the callable itself are reported.
+	call(i *interpreter, paren Token, arguments []interface{}) interface{}
 }
 
-func (i *interpreter) Interpret(statements []Stmt) {
+// Variadic is the arity of the callables that accept any number of arguments.
+const Variadic = -1
+
+// returnValue is passed up the execute chain by a return statement until it reaches the function call.
+type returnValue struct {
+	value interface{}
+}
+
+// breakValue and continueValue are passed up the execute chain by break and continue statements until they
+// reach the enclosing loop.
+type breakValue struct{}
+type continueValue struct{}
+
+func NewInterpreter(options ...InterpreterOption) *interpreter {
+	globals := newEnvironment()
+	i := &interpreter{
+		globals: globals,
+		env:     globals,
+		locals:  make(map[Expr]int),
+		output:  os.Stdout,
+		ctx:     context.Background(),
+
+		maxCallDepth: defaultMaxCallDepth,
+	}
+	for _, option := range options {
+		option(i)
+	}
+	defineNatives(i)
+	return i
+}
+
+// Interpret executes statements until the end or until a runtime error, which is reported on the error
+// output, if any, and returned.
+func (i *interpreter) Interpret(statements []Stmt) error {
+	return i.InterpretContext(context.Background(), statements)
+}
+
+// InterpretContext is like Interpret, but stops with a runtime error wrapping the error of ctx when ctx is
+// done.
+func (i *interpreter) InterpretContext(ctx context.Context, statements []Stmt) error {
+	defer i.start(ctx)()
 	for _, statement := range statements {
-		err := i.execute(statement)
-		if err != nil {
-			fmt.Println(err.(*runtimeError).Error())
-			return
+		// the resolver rejects return statements at the top level, so the result is nil or an error
+		if err, ok := i.execute(statement).(*RuntimeError); ok {
+			return i.report(err, "", "")
+		}
+	}
+	return nil
+}
+
+// Define defines a global function implemented in Go, which lox code can call with arity arguments, or with
+// any number of arguments if arity is Variadic. It replaces any global of the same name.
+func (i *interpreter) Define(name string, arity int, function NativeFunction) {
+	i.globals.define(name, &nativeFunction{name: name, arityValue: arity, function: function})
+}
+
+// SetGlobal defines a global variable, or assigns it if it already exists.
+func (i *interpreter) SetGlobal(name string, value interface{}) {
+	i.globals.define(name, value)
+}
+
+// start prepares the interpreter for a run with the given context, and returns a function to call when the
+// run is over.
+func (i *interpreter) start(ctx context.Context) func() {
+	previous := i.ctx
+	i.ctx = ctx
+	i.steps = 0
+	i.frames = i.frames[:0]
+	i.allocated = 0
+	return func() { i.ctx = previous }
+}
+
+// allocate accounts for bytes allocated by the current run, and returns a runtime error at token if the run
+// exceeds its memory limit. Allocations with no token at hand are accounted for directly, and caught by the
+// next check of the limits.
+func (i *interpreter) allocate(token Token, bytes int) *RuntimeError {
+	i.allocated += bytes
+	if i.memoryLimit > 0 && i.allocated > i.memoryLimit {
+		return &RuntimeError{Token: token, Message: "Memory limit exceeded.", Err: ErrMemoryLimit}
+	}
+	return nil
+}
+
+// checkLimits returns a runtime error at token if the current run has exceeded its step or memory limit, or
+// if its context is done.
+func (i *interpreter) checkLimits(token Token) *RuntimeError {
+	if i.stepLimit > 0 && i.steps > i.stepLimit {
+		return &RuntimeError{Token: token, Message: "Step limit exceeded.", Err: ErrStepLimit}
+	}
+	if i.memoryLimit > 0 && i.allocated > i.memoryLimit {
+		return &RuntimeError{Token: token, Message: "Memory limit exceeded.", Err: ErrMemoryLimit}
+	}
+	select {
+	case <-i.ctx.Done():
+		err := i.ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &RuntimeError{Token: token, Message: "Deadline exceeded.", Err: err}
 		}
+		return &RuntimeError{Token: token, Message: "Execution canceled.", Err: err}
+	default:
+		return nil
 	}
 }
 
-// execute returns either nil or a runtime error
+// resolve is called by the resolver to record the scope distance of a local variable.
+func (i *interpreter) resolve(expr Expr, depth int) {
+	i.locals[expr] = depth
+}
+
+// execute returns either nil, a runtime error or a return value
 func (i *interpreter) execute(stmt Stmt) interface{} {
-	return stmt.Accept(i)
+	i.steps++
+	result := stmt.Accept(i)
+	// the stack trace is recorded by the innermost statement, before the calls the error happened in return
+	if err, ok := result.(*RuntimeError); ok && err.stack == "" && err.catchable() {
+		err.stack = i.stackTrace(err.Token.Line)
+	}
+	return result
 }
 
 func (i *interpreter) executeBlock(statements []Stmt, env *environment) interface{} {
@@ -43,21 +251,110 @@ func (i *interpreter) executeBlock(statements []Stmt, env *environment) interfac
 }
 
 func (i *interpreter) visitBlockStmt(stmt *BlockStmt) interface{} {
+	i.allocated += environmentSize
 	return i.executeBlock(stmt.statements, newScopedEnvironment(i.env))
 }
 
+func (i *interpreter) visitBreakStmt(stmt *BreakStmt) interface{} {
+	return &breakValue{}
+}
+
+func (i *interpreter) visitClassStmt(stmt *ClassStmt) interface{} {
+	var superclass *loxClass = nil
+	if stmt.superclass != nil {
+		value := i.evaluate(stmt.superclass)
+		err, ok := value.(*RuntimeError)
+		if ok {
+			return err
+		}
+		superclass, ok = value.(*loxClass)
+		if !ok {
+			return &RuntimeError{Token: stmt.superclass.name, Message: "Superclass must be a class."}
+		}
+	}
+
+	i.env.define(stmt.name.Lexeme, nil)
+
+	if superclass != nil {
+		i.env = newScopedEnvironment(i.env)
+		i.env.define("super", superclass)
+	}
+	methods := make(map[string]*loxFunction)
+	for _, method := range stmt.methods {
+		methods[method.name.Lexeme] = newLoxFunction(method, i.env, method.name.Lexeme == "init")
+	}
+	class := newLoxClass(stmt.name.Lexeme, superclass, methods)
+	if superclass != nil {
+		i.env = i.env.enclosing
+	}
+
+	err := i.env.assign(stmt.name, class)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *interpreter) visitContinueStmt(stmt *ContinueStmt) interface{} {
+	return &continueValue{}
+}
+
 func (i *interpreter) visitExpressionStmt(stmt *ExpressionStmt) interface{} {
 	expr := i.evaluate(stmt.expression)
-	err, ok := expr.(*runtimeError)
+	err, ok := expr.(*RuntimeError)
 	if ok {
 		return err
 	}
 	return nil
 }
 
+func (i *interpreter) visitForInStmt(stmt *ForInStmt) interface{} {
+	iterable := i.evaluate(stmt.iterable)
+	if err, ok := iterable.(*RuntimeError); ok {
+		return err
+	}
+	it, err := i.iterator(iterable, stmt.in)
+	if err != nil {
+		return err
+	}
+	for {
+		if err := i.checkLimits(stmt.keyword); err != nil {
+			return err
+		}
+		value, ok, err := it.next(i)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		// the memory limit is checked by the next iteration
+		i.allocated += environmentSize + entrySize
+		env := newScopedEnvironment(i.env)
+		env.define(stmt.name.Lexeme, value)
+		switch result := i.executeBlock([]Stmt{stmt.body}, env).(type) {
+		case nil, *continueValue:
+		case *breakValue:
+			return nil
+		default:
+			// runtime error or return value
+			return result
+		}
+	}
+}
+
+func (i *interpreter) visitFunctionStmt(stmt *FunctionStmt) interface{} {
+	if err := i.allocate(stmt.name, functionSize+entrySize); err != nil {
+		return err
+	}
+	function := newLoxFunction(stmt, i.env, false)
+	i.env.define(stmt.name.Lexeme, function)
+	return nil
+}
+
 func (i *interpreter) visitIfStmt(stmt *IfStmt) interface{} {
 	condition := i.evaluate(stmt.condition)
-	err, ok := condition.(*runtimeError)
+	err, ok := condition.(*RuntimeError)
 	if ok {
 		return err
 	}
@@ -67,7 +364,7 @@ func (i *interpreter) visitIfStmt(stmt *IfStmt) interface{} {
 			return err
 		}
 	} else if stmt.elseBranch != nil {
-		err := i.execute(stmt.thenBranch)
+		err := i.execute(stmt.elseBranch)
 		if err != nil {
 			return err
 		}
@@ -77,18 +374,69 @@ func (i *interpreter) visitIfStmt(stmt *IfStmt) interface{} {
 
 func (i *interpreter) visitPrintStmt(stmt *PrintStmt) interface{} {
 	value := i.evaluate(stmt.expression)
-	err, ok := value.(*runtimeError)
+	err, ok := value.(*RuntimeError)
 	if ok {
 		return err
 	}
-	fmt.Println(stringify(value))
+	fmt.Fprintln(i.output, stringify(value))
 	return nil
 }
 
+func (i *interpreter) visitReturnStmt(stmt *ReturnStmt) interface{} {
+	var value interface{} = nil
+	if stmt.value != nil {
+		value = i.evaluate(stmt.value)
+		err, ok := value.(*RuntimeError)
+		if ok {
+			return err
+		}
+	}
+	return &returnValue{value: value}
+}
+
+func (i *interpreter) visitThrowStmt(stmt *ThrowStmt) interface{} {
+	value := i.evaluate(stmt.value)
+	if err, ok := value.(*RuntimeError); ok {
+		return err
+	}
+	return &RuntimeError{Token: stmt.keyword, Message: i.thrownMessage(value), thrown: true, value: value}
+}
+
+func (i *interpreter) visitTryStmt(stmt *TryStmt) interface{} {
+	i.allocated += environmentSize
+	result := i.executeBlock(stmt.body, newScopedEnvironment(i.env))
+	if err, ok := result.(*RuntimeError); ok && stmt.catchBody != nil && err.catchable() {
+		i.allocated += environmentSize + entrySize
+		env := newScopedEnvironment(i.env)
+		env.define(stmt.catchName.Lexeme, i.caughtValue(err))
+		result = i.executeBlock(stmt.catchBody, env)
+	}
+	if err, ok := result.(*RuntimeError); ok && !err.catchable() {
+		// the run is stopping: the finally body doesn't run so that it can't resume it
+		return err
+	}
+	if stmt.finallyBody != nil {
+		// the finally body runs however the try statement ends, and replaces the way it ends if it doesn't
+		// complete normally itself
+		i.allocated += environmentSize
+		if finally := i.executeBlock(stmt.finallyBody, newScopedEnvironment(i.env)); finally != nil {
+			return finally
+		}
+	}
+	return result
+}
+
 func (i *interpreter) visitVarStmt(stmt *VarStmt) interface{} {
 	var value interface{} = nil
 	if stmt.initializer != nil {
 		value = i.evaluate(stmt.initializer)
+		err, ok := value.(*RuntimeError)
+		if ok {
+			return err
+		}
+	}
+	if err := i.allocate(stmt.name, entrySize); err != nil {
+		return err
 	}
 	i.env.define(stmt.name.Lexeme, value)
 	return nil
@@ -96,17 +444,30 @@ func (i *interpreter) visitVarStmt(stmt *VarStmt) interface{} {
 
 func (i *interpreter) visitWhileStmt(stmt *WhileStmt) interface{} {
 	for {
+		if err := i.checkLimits(stmt.keyword); err != nil {
+			return err
+		}
 		condition := i.evaluate(stmt.condition)
-		errCondition, ok := condition.(*runtimeError)
+		errCondition, ok := condition.(*RuntimeError)
 		if ok {
 			return errCondition
 		}
 		if !isTruthy(condition) {
 			break
 		}
-		err := i.execute(stmt.body)
-		if err != nil {
-			return err
+		switch result := i.execute(stmt.body).(type) {
+		case nil, *continueValue:
+		case *breakValue:
+			return nil
+		default:
+			// runtime error or return value
+			return result
+		}
+		if stmt.increment != nil {
+			increment := i.evaluate(stmt.increment)
+			if err, ok := increment.(*RuntimeError); ok {
+				return err
+			}
 		}
 	}
 	return nil
@@ -118,11 +479,15 @@ func (i *interpreter) evaluate(expr Expr) interface{} {
 
 func (i *interpreter) visitAssignExpr(expr *AssignExpr) interface{} {
 	value := i.evaluate(expr.value)
-	err, ok := value.(*runtimeError)
+	err, ok := value.(*RuntimeError)
 	if ok {
 		return err
 	}
-	err = i.env.assign(expr.name, value)
+	if distance, ok := i.locals[expr]; ok {
+		i.env.assignAt(distance, expr.name, value)
+		return value
+	}
+	err = i.globals.assign(expr.name, value)
 	if err != nil {
 		return err
 	}
@@ -131,12 +496,12 @@ func (i *interpreter) visitAssignExpr(expr *AssignExpr) interface{} {
 
 func (i *interpreter) visitBinaryExpr(expr *BinaryExpr) interface{} {
 	left := i.evaluate(expr.left)
-	err, ok := left.(*runtimeError)
+	err, ok := left.(*RuntimeError)
 	if ok {
 		return err
 	}
 	right := i.evaluate(expr.right)
-	err, ok = right.(*runtimeError)
+	err, ok = right.(*RuntimeError)
 	if ok {
 		return err
 	}
@@ -176,7 +541,7 @@ func (i *interpreter) visitBinaryExpr(expr *BinaryExpr) interface{} {
 			return err
 		}
 		if castedRight == 0 {
-			return &runtimeError{token: expr.operator, message: "Right operand must not be 0."}
+			return &RuntimeError{Token: expr.operator, Message: "Right operand must not be 0."}
 		}
 		return castedLeft / castedRight
 	case Star:
@@ -198,26 +563,181 @@ func (i *interpreter) visitBinaryExpr(expr *BinaryExpr) interface{} {
 		}
 		leftString, rightString, err := castStringOperands(expr.operator, left, right)
 		if err == nil {
+			// account for the string before building it, which could exhaust the memory otherwise
+			if err := i.allocate(expr.operator, stringSize+len(leftString)+len(rightString)); err != nil {
+				return err
+			}
 			return leftString + rightString
 		}
-		return &runtimeError{token: expr.operator, message: "Operands must be two numbers or two strings."}
+		return &RuntimeError{Token: expr.operator, Message: "Operands must be two numbers or two strings."}
 	}
 
 	// unreachable
 	return nil
 }
 
+func (i *interpreter) visitCallExpr(expr *CallExpr) interface{} {
+	callee := i.evaluate(expr.callee)
+	err, ok := callee.(*RuntimeError)
+	if ok {
+		return err
+	}
+
+	arguments := make([]interface{}, 0, len(expr.arguments))
+	for _, argument := range expr.arguments {
+		value := i.evaluate(argument)
+		err, ok := value.(*RuntimeError)
+		if ok {
+			return err
+		}
+		arguments = append(arguments, value)
+	}
+
+	function, ok := callee.(LoxCallable)
+	if !ok {
+		return &RuntimeError{Token: expr.paren, Message: "Can only call functions and classes."}
+	}
+	return i.callFunction(function, expr.paren, arguments)
+}
+
+// callFunction calls function with arguments after checking its arity and the limits of the run. Errors are
+// located at token.
+func (i *interpreter) callFunction(function LoxCallable, token Token, arguments []interface{}) interface{} {
+	if function.arity() != Variadic && len(arguments) != function.arity() {
+		return &RuntimeError{
+			Token:   token,
+			Message: fmt.Sprintf("Expected %d arguments but got %d.", function.arity(), len(arguments)),
+		}
+	}
+	if err := i.checkLimits(token); err != nil {
+		return err
+	}
+	if i.maxCallDepth > 0 && len(i.frames) >= i.maxCallDepth {
+		return &RuntimeError{Token: token, Message: "Stack overflow.", Err: ErrStackOverflow}
+	}
+	i.frames = append(i.frames, interpreterFrame{function: function, call: token})
+	defer func() { i.frames = i.frames[:len(i.frames)-1] }()
+	return function.call(i, token, arguments)
+}
+
+func (i *interpreter) visitGetExpr(expr *GetExpr) interface{} {
+	object := i.evaluate(expr.object)
+	err, ok := object.(*RuntimeError)
+	if ok {
+		return err
+	}
+	if host, ok := object.(HostObject); ok {
+		value, err := host.Get(expr.name.Lexeme)
+		if err != nil {
+			return &RuntimeError{Token: expr.name, Message: err.Error()}
+		}
+		return value
+	}
+	instance, ok := object.(*loxInstance)
+	if !ok {
+		return &RuntimeError{Token: expr.name, Message: "Only instances have properties."}
+	}
+	value, err := instance.get(expr.name)
+	if err != nil {
+		return err
+	}
+	return value
+}
+
 func (i *interpreter) visitGroupingExpr(expr *GroupingExpr) interface{} {
 	return i.evaluate(expr.expression)
 }
 
+func (i *interpreter) visitIndexExpr(expr *IndexExpr) interface{} {
+	object := i.evaluate(expr.object)
+	if err, ok := object.(*RuntimeError); ok {
+		return err
+	}
+	index := i.evaluate(expr.index)
+	if err, ok := index.(*RuntimeError); ok {
+		return err
+	}
+	switch object := object.(type) {
+	case *loxList:
+		idx, err := listIndex(index, len(object.elements))
+		if err != nil {
+			return &RuntimeError{Token: expr.bracket, Message: err.Error()}
+		}
+		return object.elements[idx]
+	case *loxMap:
+		if err := checkMapKey(index); err != nil {
+			return &RuntimeError{Token: expr.bracket, Message: err.Error()}
+		}
+		value, ok := object.get(index)
+		if !ok {
+			return &RuntimeError{Token: expr.bracket, Message: fmt.Sprintf("Undefined key %s.", stringifyElement(index))}
+		}
+		return value
+	}
+	return &RuntimeError{Token: expr.bracket, Message: "Only lists and maps can be indexed."}
+}
+
+func (i *interpreter) visitIndexSetExpr(expr *IndexSetExpr) interface{} {
+	object := i.evaluate(expr.object)
+	if err, ok := object.(*RuntimeError); ok {
+		return err
+	}
+	index := i.evaluate(expr.index)
+	if err, ok := index.(*RuntimeError); ok {
+		return err
+	}
+	switch object.(type) {
+	case *loxList, *loxMap:
+	default:
+		return &RuntimeError{Token: expr.bracket, Message: "Only lists and maps can be indexed."}
+	}
+	value := i.evaluate(expr.value)
+	if err, ok := value.(*RuntimeError); ok {
+		return err
+	}
+	if m, ok := object.(*loxMap); ok {
+		if err := checkMapKey(index); err != nil {
+			return &RuntimeError{Token: expr.bracket, Message: err.Error()}
+		}
+		if _, ok := m.get(index); !ok {
+			if err := i.allocate(expr.bracket, loxMapEntrySize); err != nil {
+				return err
+			}
+		}
+		m.set(index, value)
+		return value
+	}
+	list := object.(*loxList)
+	idx, err := listIndex(index, len(list.elements))
+	if err != nil {
+		return &RuntimeError{Token: expr.bracket, Message: err.Error()}
+	}
+	list.elements[idx] = value
+	return value
+}
+
+func (i *interpreter) visitListExpr(expr *ListExpr) interface{} {
+	elements := make([]interface{}, 0, len(expr.elements))
+	for _, element := range expr.elements {
+		value := i.evaluate(element)
+		if err, ok := value.(*RuntimeError); ok {
+			return err
+		}
+		elements = append(elements, value)
+	}
+	if err := i.allocate(expr.bracket, listSize+len(elements)*elementSize); err != nil {
+		return err
+	}
+	return newLoxList(elements)
+}
+
 func (i *interpreter) visitLiteralExpr(expr *LiteralExpr) interface{} {
 	return expr.value
 }
 
 func (i *interpreter) visitLogicalExpr(expr *LogicalExpr) interface{} {
 	left := i.evaluate(expr.left)
-	err, ok := left.(*runtimeError)
+	err, ok := left.(*RuntimeError)
 	if ok {
 		return err
 	}
@@ -234,9 +754,113 @@ func (i *interpreter) visitLogicalExpr(expr *LogicalExpr) interface{} {
 	return i.evaluate(expr.right)
 }
 
+func (i *interpreter) visitMapExpr(expr *MapExpr) interface{} {
+	m := newLoxMap()
+	for idx, keyExpr := range expr.keys {
+		key := i.evaluate(keyExpr)
+		if err, ok := key.(*RuntimeError); ok {
+			return err
+		}
+		value := i.evaluate(expr.values[idx])
+		if err, ok := value.(*RuntimeError); ok {
+			return err
+		}
+		if err := checkMapKey(key); err != nil {
+			return &RuntimeError{Token: expr.brace, Message: err.Error()}
+		}
+		m.set(key, value)
+	}
+	if err := i.allocate(expr.brace, loxMapSize+len(m.keys)*loxMapEntrySize); err != nil {
+		return err
+	}
+	return m
+}
+
+func (i *interpreter) visitSetExpr(expr *SetExpr) interface{} {
+	object := i.evaluate(expr.object)
+	err, ok := object.(*RuntimeError)
+	if ok {
+		return err
+	}
+	host, isHost := object.(HostObject)
+	instance, ok := object.(*loxInstance)
+	if !ok && !isHost {
+		return &RuntimeError{Token: expr.name, Message: "Only instances have fields."}
+	}
+	value := i.evaluate(expr.value)
+	err, ok = value.(*RuntimeError)
+	if ok {
+		return err
+	}
+	if isHost {
+		if err := host.Set(expr.name.Lexeme, value); err != nil {
+			return &RuntimeError{Token: expr.name, Message: err.Error()}
+		}
+		return value
+	}
+	if _, ok := instance.fields[expr.name.Lexeme]; !ok {
+		if err := i.allocate(expr.name, entrySize); err != nil {
+			return err
+		}
+	}
+	instance.set(expr.name, value)
+	return value
+}
+
+func (i *interpreter) visitSliceExpr(expr *SliceExpr) interface{} {
+	object := i.evaluate(expr.object)
+	if err, ok := object.(*RuntimeError); ok {
+		return err
+	}
+	// the bounds default to the whole list
+	bounds := []interface{}{nil, nil}
+	for idx, bound := range []Expr{expr.start, expr.end} {
+		if bound == nil {
+			continue
+		}
+		bounds[idx] = i.evaluate(bound)
+		if err, ok := bounds[idx].(*RuntimeError); ok {
+			return err
+		}
+	}
+	list, ok := object.(*loxList)
+	if !ok {
+		return &RuntimeError{Token: expr.bracket, Message: "Only lists can be sliced."}
+	}
+	start, end, err := sliceBounds(bounds[0], bounds[1], len(list.elements))
+	if err != nil {
+		return &RuntimeError{Token: expr.bracket, Message: err.Error()}
+	}
+	if err := i.allocate(expr.bracket, listSize+(end-start)*elementSize); err != nil {
+		return err
+	}
+	elements := make([]interface{}, end-start)
+	copy(elements, list.elements[start:end])
+	return newLoxList(elements)
+}
+
+func (i *interpreter) visitSuperExpr(expr *SuperExpr) interface{} {
+	distance := i.locals[expr]
+	superclass := i.env.getAt(distance, "super").(*loxClass)
+	// "this" is always bound in the scope right inside the one where "super" is
+	object := i.env.getAt(distance-1, "this").(*loxInstance)
+	method, ok := superclass.findMethod(expr.method.Lexeme)
+	if !ok {
+		return &RuntimeError{
+			Token:   expr.method,
+			Message: fmt.Sprintf("Undefined property '%s'.", expr.method.Lexeme),
+		}
+	}
+	return method.bind(object)
+}
+
+func (i *interpreter) visitThisExpr(expr *ThisExpr) interface{} {
+	return i.lookUpVariable(expr.keyword, expr)
+}
+
 func (i *interpreter) visitUnaryExpr(expr *UnaryExpr) interface{} {
 	right := i.evaluate(expr.right)
-	err, ok := right.(*runtimeError)
+	err, ok := right.(*RuntimeError)
 	if ok {
 		return err
 	}
@@ -257,7 +881,14 @@ func (i *interpreter) visitUnaryExpr(expr *UnaryExpr) interface{} {
 }
 
 func (i *interpreter) visitVariableExpr(expr *VariableExpr) interface{} {
-	value, err := i.env.get(expr.name)
+	return i.lookUpVariable(expr.name, expr)
+}
+
+func (i *interpreter) lookUpVariable(name Token, expr Expr) interface{} {
+	if distance, ok := i.locals[expr]; ok {
+		return i.env.getAt(distance, name.Lexeme)
+	}
+	value, err := i.globals.get(name)
 	if err != nil {
 		return err
 	}
@@ -291,37 +922,59 @@ func stringify(value interface{}) string {
 	return fmt.Sprintf("%v", value)
 }
 
-type runtimeError struct {
-	token   Token
-	message string
+// Errors wrapped by the runtime errors raised when a run exceeds the limits of the interpreter. When the
+// context of a run is done, the runtime error wraps the error of the context instead.
+var (
+	ErrStepLimit     = errors.New("step limit exceeded")
+	ErrStackOverflow = errors.New("stack overflow")
+	ErrMemoryLimit   = errors.New("memory limit exceeded")
+)
+
+// RuntimeError is an error raised while interpreting.
+type RuntimeError struct {
+	// Token is where the error happened.
+	Token   Token
+	Message string
+	// Err is the cause of the error, if any.
+	Err error
+
+	// thrown is true for the errors raised by throw statements, which throw value.
+	thrown bool
+	value  interface{}
+	// stack is the stack trace of the error, innermost call first.
+	stack string
+}
+
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("%s: %s\n[%s]", e.Token.Lexeme, e.Message, e.Token.Span())
 }
 
-func (e *runtimeError) Error() string {
-	return fmt.Sprintf("%s: %s\n[line %d]", e.token.Lexeme, e.message, e.token.Line)
+func (e *RuntimeError) Unwrap() error {
+	return e.Err
 }
 
-func castNumberOperand(operator Token, operand interface{}) (float64, *runtimeError) {
+func castNumberOperand(operator Token, operand interface{}) (float64, *RuntimeError) {
 	casted, ok := operand.(float64)
 	if !ok {
-		return 0, &runtimeError{token: operator, message: "Operand must be a number."}
+		return 0, &RuntimeError{Token: operator, Message: "Operand must be a number."}
 	}
 	return casted, nil
 }
 
-func castNumberOperands(operator Token, left, right interface{}) (float64, float64, *runtimeError) {
+func castNumberOperands(operator Token, left, right interface{}) (float64, float64, *RuntimeError) {
 	castedLeft, okLeft := left.(float64)
 	castedRight, okRight := right.(float64)
 	if !okLeft || !okRight {
-		return 0, 0, &runtimeError{token: operator, message: "Operands must be numbers."}
+		return 0, 0, &RuntimeError{Token: operator, Message: "Operands must be numbers."}
 	}
 	return castedLeft, castedRight, nil
 }
 
-func castStringOperands(operator Token, left, right interface{}) (string, string, *runtimeError) {
+func castStringOperands(operator Token, left, right interface{}) (string, string, *RuntimeError) {
 	castedLeft, okLeft := left.(string)
 	castedRight, okRight := right.(string)
 	if !okLeft || !okRight {
-		return "", "", &runtimeError{token: operator, message: "Operands must be strings."}
+		return "", "", &RuntimeError{Token: operator, Message: "Operands must be strings."}
 	}
 	return castedLeft, castedRight, nil
 }
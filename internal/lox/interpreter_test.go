@@ -0,0 +1,843 @@
+package lox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// interpret runs source with a fresh interpreter and returns what it printed, followed by the runtime error
+// if any.
+func interpret(t *testing.T, source string) string {
+	t.Helper()
+	statements, interpreter := resolve(t, source)
+	var output bytes.Buffer
+	interpreter.output = &output
+	interpreter.errorOutput = &output
+	_ = interpreter.Interpret(statements)
+	return output.String()
+}
+
+// resolve scans, parses and resolves source for a fresh interpreter.
+func resolve(t *testing.T, source string) ([]Stmt, *interpreter) {
+	t.Helper()
+	scanner := NewScanner(source)
+	tokens := scanner.ScanTokens()
+	require.Empty(t, scanner.Errors())
+	parser := NewParser(tokens)
+	statements := parser.Parse()
+	require.Empty(t, parser.Errors())
+	interpreter := NewInterpreter()
+	resolver := NewResolver(interpreter)
+	resolver.Resolve(statements)
+	require.Empty(t, resolver.Errors())
+	return statements, interpreter
+}
+
+func TestInterpreterFunctions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name: "recursion",
+			source: `
+fun fib(n) {
+  if (n <= 1) return n;
+  return fib(n - 2) + fib(n - 1);
+}
+print fib(10);`,
+			expected: "55\n",
+		},
+		{
+			name: "implicit nil return",
+			source: `
+fun sayHi(first, last) {
+  print "Hi, " + first + " " + last + "!";
+}
+print sayHi("Dear", "Reader");`,
+			expected: "Hi, Dear Reader!\nnil\n",
+		},
+		{
+			name: "return unwinds loops and blocks",
+			source: `
+fun firstAbove(limit) {
+  for (var i = 0; i < 100; i = i + 1) {
+    {
+      if (i > limit) return i;
+    }
+  }
+  return -1;
+}
+print firstAbove(3);`,
+			expected: "4\n",
+		},
+		{
+			name:     "functions are values",
+			source:   "fun f() {} var g = f; print g; print clock;",
+			expected: "<fn f>\n<native fn>\n",
+		},
+		{
+			name:     "wrong arity",
+			source:   "fun f(a, b) {} f(1);",
+			expected: "): Expected 2 arguments but got 1.\n[line 1:19]\n",
+		},
+		{
+			name:     "call a non callable",
+			source:   `"not a function"();`,
+			expected: "): Can only call functions and classes.\n[line 1:18]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source))
+		})
+	}
+}
+
+func TestInterpreterClosures(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name: "counter",
+			source: `
+fun makeCounter() {
+  var i = 0;
+  fun count() {
+    i = i + 1;
+    print i;
+  }
+  return count;
+}
+var counter = makeCounter();
+counter();
+counter();
+var other = makeCounter();
+other();`,
+			expected: "1\n2\n1\n",
+		},
+		{
+			name: "free variables resolve in the declaring environment",
+			source: `
+var x = "global";
+fun makeShow() {
+  var x = "captured";
+  fun show() {
+    print x;
+  }
+  return show;
+}
+fun caller(f) {
+  var x = "caller";
+  f();
+}
+caller(makeShow());`,
+			expected: "captured\n",
+		},
+		{
+			name: "block locals are captured per loop iteration",
+			source: `
+var first;
+var second;
+for (var i = 0; i < 2; i = i + 1) {
+  var j = i;
+  fun show() {
+    print j;
+  }
+  if (first == nil) first = show; else second = show;
+}
+first();
+second();`,
+			expected: "0\n1\n",
+		},
+		{
+			name: "later shadowing declarations are not seen",
+			source: `
+var a = "global";
+{
+  fun showA() {
+    print a;
+  }
+  showA();
+  var a = "block";
+  showA();
+}`,
+			expected: "global\nglobal\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source))
+		})
+	}
+}
+
+func TestInterpreterClasses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "class and instance values",
+			source:   "class Bagel {} var bagel = Bagel(); print Bagel; print bagel;",
+			expected: "Bagel\nBagel instance\n",
+		},
+		{
+			name: "fields",
+			source: `
+class Point {}
+var p = Point();
+p.x = 1;
+p.y = p.x + 1;
+print p.y;`,
+			expected: "2\n",
+		},
+		{
+			name: "methods are bound to this",
+			source: `
+class Egotist {
+  speak() {
+    print "Just " + this.name;
+  }
+}
+var jimmy = Egotist();
+jimmy.name = "Jimmy";
+var method = jimmy.speak;
+method();`,
+			expected: "Just Jimmy\n",
+		},
+		{
+			name: "initializer",
+			source: `
+class Counter {
+  init(start) {
+    this.count = start;
+    return;
+  }
+  increment() {
+    this.count = this.count + 1;
+    return this;
+  }
+}
+var counter = Counter(41);
+print counter.increment().count;
+print counter.init(0) == counter;
+print counter.count;`,
+			expected: "42\ntrue\n0\n",
+		},
+		{
+			name:     "undefined property",
+			source:   "class A {} A().missing;",
+			expected: "missing: Undefined property 'missing'.\n[line 1:16]\n",
+		},
+		{
+			name:     "properties on non-instances",
+			source:   `"str".length;`,
+			expected: "length: Only instances have properties.\n[line 1:7]\n",
+		},
+		{
+			name:     "initializer arity",
+			source:   "class A { init(a) {} } A();",
+			expected: "): Expected 1 arguments but got 0.\n[line 1:26]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source))
+		})
+	}
+}
+
+func TestInterpreterInheritance(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name: "inherited methods",
+			source: `
+class Doughnut {
+  cook() {
+    print "Fry until golden brown.";
+  }
+}
+class BostonCream < Doughnut {}
+BostonCream().cook();`,
+			expected: "Fry until golden brown.\n",
+		},
+		{
+			name: "super calls",
+			source: `
+class A {
+  method() {
+    print "A method";
+  }
+}
+class B < A {
+  method() {
+    print "B method";
+  }
+  test() {
+    super.method();
+  }
+}
+class C < B {}
+C().test();`,
+			expected: "A method\n",
+		},
+		{
+			name: "super initializer",
+			source: `
+class Base {
+  init(name) {
+    this.name = name;
+  }
+}
+class Derived < Base {
+  init(name) {
+    super.init(name + "!");
+  }
+}
+print Derived("hello").name;`,
+			expected: "hello!\n",
+		},
+		{
+			name:     "inherit from a non-class",
+			source:   "var NotAClass = \"nope\";\nclass A < NotAClass {}",
+			expected: "NotAClass: Superclass must be a class.\n[line 2:11]\n",
+		},
+		{
+			name:     "undefined super method",
+			source:   "class A {}\nclass B < A {\n  f() {\n    super.missing();\n  }\n}\nB().f();",
+			expected: "missing: Undefined property 'missing'.\n[line 4:11]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source))
+		})
+	}
+}
+
+func TestInterpreterLimits(t *testing.T) {
+	run := func(ctx context.Context, source string, options ...InterpreterOption) (string, error) {
+		var output bytes.Buffer
+		interpreter := NewInterpreter(append(options, WithOutput(&output))...)
+		err := interpreter.RunContext(ctx, "", source)
+		return output.String(), err
+	}
+
+	output, err := run(context.Background(), "var i = 0;\nwhile (true) {\n  i = i + 1;\n}", WithStepLimit(100))
+	assert.True(t, errors.Is(err, ErrStepLimit))
+	assert.Equal(t, "while: Step limit exceeded.\n[line 2:1]", err.Error())
+
+	// limits can't be caught, and finally bodies don't run once they are exceeded
+	output, err = run(context.Background(), "try {\n  while (true) {}\n} catch (e) {\n  print e;\n} finally {\n  print \"finally\";\n}", WithStepLimit(100))
+	assert.True(t, errors.Is(err, ErrStepLimit))
+	assert.Empty(t, output)
+
+	// iterators can be endless too
+	_, err = run(context.Background(), "class Forever {\n  hasNext() { return true; }\n  next() { return 1; }\n}\nfor (var x in Forever()) {}", WithStepLimit(100))
+	assert.True(t, errors.Is(err, ErrStepLimit))
+
+	// the budget is per run, and the statements of a run below it run normally
+	interpreter := NewInterpreter(WithStepLimit(20), WithOutput(&bytes.Buffer{}))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, interpreter.Run("", "for (var i = 0; i < 3; i = i + 1) print i;"))
+	}
+
+	output, err = run(context.Background(), "fun f(n) {\n  print n;\n  f(n + 1);\n}\nf(0);", WithMaxCallDepth(3))
+	assert.True(t, errors.Is(err, ErrStackOverflow))
+	assert.Equal(t, "0\n1\n2\n", output)
+	assert.Equal(t, "): Stack overflow.\n[line 3:10]", err.Error())
+
+	// the default depth stops infinite recursions before they crash
+	_, err = run(context.Background(), "fun f() { f(); } f();")
+	assert.True(t, errors.Is(err, ErrStackOverflow))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = run(ctx, "fun f() {} f();")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, "): Execution canceled.\n[line 1:14]", err.Error())
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = run(ctx, "while (true) {}")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.Equal(t, "Deadline exceeded.", runtimeErr.Message)
+}
+
+func TestInterpreterMemoryLimit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "string concatenation",
+			source:   "var s = \"ab\";\nfor (var i = 0; i < 30; i = i + 1) s = s + s;",
+			expected: "+: Memory limit exceeded.\n[line 2:42]",
+		},
+		{
+			name:     "instances",
+			source:   "class A {}\nvar a;\nwhile (true) a = A();",
+			expected: "): Memory limit exceeded.\n[line 3:20]",
+		},
+		{
+			name:     "fields",
+			source:   "class A {}\nvar a = A();\nwhile (true) {\n  a.field = a;\n  a = A();\n}",
+			expected: "field: Memory limit exceeded.\n[line 4:5]",
+		},
+		{
+			name:     "environments",
+			source:   "fun f() {}\nwhile (true) f();",
+			expected: "): Memory limit exceeded.\n[line 2:16]",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			interpreter := NewInterpreter(WithMemoryLimit(64*1024), WithOutput(&bytes.Buffer{}))
+			err := interpreter.Run("", tc.source)
+			assert.True(t, errors.Is(err, ErrMemoryLimit))
+			require.Error(t, err)
+			assert.Equal(t, tc.expected, err.Error())
+		})
+	}
+
+	// the limit applies to each run
+	interpreter := NewInterpreter(WithMemoryLimit(1024), WithOutput(&bytes.Buffer{}))
+	for i := 0; i < 100; i++ {
+		require.NoError(t, interpreter.Run("", `var s = "a" + "b";`))
+	}
+}
+
+func TestInterpreterLists(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "literals",
+			source:   `print []; print [1, "two", nil, [true]]; print [1 + 2][0];`,
+			expected: "[]\n[1, \"two\", nil, [true]]\n3\n",
+		},
+		{
+			name:     "index get and set",
+			source:   "var xs = [1, 2, 3];\nxs[0] = xs[2] * 10;\nxs[1] = xs;\nprint xs[0];\nprint xs[1][0];\nprint xs[1] == xs;\nprint [1] == [1];",
+			expected: "30\n30\ntrue\nfalse\n",
+		},
+		{
+			name:     "built-ins",
+			source:   "var xs = [];\npush(xs, 1);\npush(xs, 3);\ninsert(xs, 1, 2);\ninsert(xs, 3, 4);\nprint xs;\nprint len(xs);\nprint pop(xs);\nprint remove(xs, 0);\nprint xs;\nprint len(\"héllo\");",
+			expected: "[1, 2, 3, 4]\n4\n4\n1\n[2, 3]\n5\n",
+		},
+		{
+			name:     "slices",
+			source:   "var xs = [0, 1, 2, 3];\nprint xs[1:3];\nprint xs[:2];\nprint xs[2:];\nprint xs[:];\nprint xs[4:];\nvar copy = xs[:];\ncopy[0] = 9;\nprint xs[0];",
+			expected: "[1, 2]\n[0, 1]\n[2, 3]\n[0, 1, 2, 3]\n[]\n0\n",
+		},
+		{
+			name:     "negative index",
+			source:   "var xs = [1];\nprint xs[-1];",
+			expected: "]: List index can't be negative, got -1.\n[line 2:12]\n",
+		},
+		{
+			name:     "index out of range",
+			source:   "var xs = [1, 2];\nxs[2] = 3;",
+			expected: "]: List index 2 out of range for a list of length 2.\n[line 2:5]\n",
+		},
+		{
+			name:     "index not an integer",
+			source:   "[1][0.5];",
+			expected: "]: List index must be an integer, got 0.5.\n[line 1:8]\n",
+		},
+		{
+			name:     "index not a number",
+			source:   `[1]["0"];`,
+			expected: "]: List index must be a number, got string.\n[line 1:8]\n",
+		},
+		{
+			name:     "index a non-list",
+			source:   `var s = "abc"; s[0];`,
+			expected: "]: Only lists and maps can be indexed.\n[line 1:19]\n",
+		},
+		{
+			name:     "slice out of range",
+			source:   "[1, 2][1:3];",
+			expected: "]: List index 3 out of range for a list of length 2.\n[line 1:11]\n",
+		},
+		{
+			name:     "slice bounds reversed",
+			source:   "[1, 2][2:1];",
+			expected: "]: Slice start 2 is after its end 1.\n[line 1:11]\n",
+		},
+		{
+			name:     "pop from an empty list",
+			source:   "pop([]);",
+			expected: "): Can't pop from an empty list.\n[line 1:7]\n",
+		},
+		{
+			name:     "built-in on a non-list",
+			source:   "push(nil, 1);",
+			expected: "): push() expects a list, got nil.\n[line 1:12]\n",
+		},
+		{
+			name:     "insert out of range",
+			source:   "insert([], 1, 1);",
+			expected: "): List index 1 out of range for a list of length 0.\n[line 1:16]\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source))
+		})
+	}
+}
+
+func TestInterpreterMaps(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "literals",
+			source:   `print {}; print {"a": 1, 2: [true], nil: {false: "no"}}; print {"a": 1}["a"];`,
+			expected: "{}\n{\"a\": 1, 2: [true], nil: {false: \"no\"}}\n1\n",
+		},
+		{
+			name:     "map literal statement",
+			source:   "{\"a\": 1}[\"a\"];\n{}\n{ print 1; }",
+			expected: "1\n",
+		},
+		{
+			name:     "get and set",
+			source:   "var m = {\"a\": 1};\nm[\"b\"] = 2;\nm[\"a\"] = m[\"a\"] + 10;\nprint m;\nprint len(m);\nprint m == m;\nprint {} == {};",
+			expected: "{\"a\": 11, \"b\": 2}\n2\ntrue\nfalse\n",
+		},
+		{
+			name:     "keys consistent with equality",
+			source:   "var m = {};\nm[0] = \"zero\";\nm[-0] = \"minus zero\";\nm[1] = \"one\";\nm[\"1\"] = \"string\";\nm[true] = \"true\";\nm[nil] = \"nil\";\nprint m;\nprint m[2 - 1];\nprint m[\"\" + \"1\"];",
+			expected: "{0: \"minus zero\", 1: \"one\", \"1\": \"string\", true: \"true\", nil: \"nil\"}\none\nstring\n",
+		},
+		{
+			name:     "built-ins",
+			source:   "var m = {\"a\": 1, \"b\": 2, \"c\": 3};\nprint has(m, \"b\");\nprint delete(m, \"b\");\nprint delete(m, \"b\");\nprint has(m, \"b\");\nm[\"b\"] = 4;\nprint keys(m);\nprint values(m);\nvar ks = keys(m);\nfor (var i = 0; i < len(ks); i = i + 1) print m[ks[i]];",
+			expected: "true\ntrue\nfalse\nfalse\n[\"a\", \"c\", \"b\"]\n[1, 3, 4]\n1\n3\n4\n",
+		},
+		{
+			name:     "undefined key",
+			source:   "var m = {\"a\": 1};\nprint m[\"b\"];",
+			expected: "]: Undefined key \"b\".\n[line 2:12]\n",
+		},
+		{
+			name:     "invalid key",
+			source:   "var m = {};\nm[[]] = 1;",
+			expected: "]: Map key must be a string, a number, a boolean or nil, got list.\n[line 2:5]\n",
+		},
+		{
+			name:     "invalid key in a literal",
+			source:   "fun f() {}\nprint {f: 1};",
+			expected: "{: Map key must be a string, a number, a boolean or nil, got function.\n[line 2:7]\n",
+		},
+		{
+			name:     "built-in on a non-map",
+			source:   "keys([]);",
+			expected: "): keys() expects a map, got list.\n[line 1:8]\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source))
+		})
+	}
+}
+
+func TestInterpreterForIn(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "lists",
+			source:   "var xs = [1, 2];\nfor (var x in xs) {\n  if (x < 3) push(xs, x + 2);\n  print x;\n}",
+			expected: "1\n2\n3\n4\n",
+		},
+		{
+			name:     "map keys",
+			source:   "var m = {\"a\": 1, \"b\": 2, \"c\": 3};\nfor (var key in m) {\n  print key;\n  delete(m, \"b\");\n  m[\"d\"] = 4;\n}\nprint m;",
+			expected: "a\nc\n{\"a\": 1, \"c\": 3, \"d\": 4}\n",
+		},
+		{
+			name:     "string characters",
+			source:   `for (var c in "héllo") print c;`,
+			expected: "h\né\nl\nl\no\n",
+		},
+		{
+			name:     "ranges",
+			source:   "for (var i in range(3)) print i;\nfor (var i in range(5, 1, -2)) print i;\nfor (var i in range(2, 2)) print i;\nprint range(1, 4);\nprint range(0, 1, 0.5);\nprint len(range(0, 10, 3));\nprint range(3) == range(0, 3);",
+			expected: "0\n1\n2\n5\n3\nrange(1, 4)\nrange(0, 1, 0.5)\n4\ntrue\n",
+		},
+		{
+			name: "iterator protocol",
+			source: `
+class Countdown {
+  init(from) { this.from = from; }
+  iterator() { return CountdownIterator(this.from); }
+}
+class CountdownIterator {
+  init(n) { this.n = n; }
+  hasNext() { return this.n > 0; }
+  next() {
+    this.n = this.n - 1;
+    return this.n + 1;
+  }
+}
+for (var n in Countdown(3)) print n;
+for (var n in CountdownIterator(2)) print n;`,
+			expected: "3\n2\n1\n2\n1\n",
+		},
+		{
+			name: "break, continue and closures",
+			source: `
+var getters = [];
+for (var x in [1, 2, 3, 4, 5]) {
+  if (x == 2) continue;
+  if (x == 4) break;
+  fun get() { return x; }
+  push(getters, get);
+}
+for (var get in getters) print get();
+fun find(xs, target) {
+  for (var x in xs) {
+    if (x == target) return "found";
+  }
+  return "missing";
+}
+print find("abc", "b");
+print find("abc", "d");`,
+			expected: "1\n3\nfound\nmissing\n",
+		},
+		{
+			name:     "not iterable",
+			source:   "for (var x in 3) print x;",
+			expected: "in: Only lists, maps, strings, ranges and iterators can be iterated over, got number.\n[line 1:12]\n",
+		},
+		{
+			name:     "instance without the protocol",
+			source:   "class A {}\nfor (var x in A()) print x;",
+			expected: "in: A instance must have an iterator() method or hasNext() and next() methods to be iterated over.\n[line 2:12]\n",
+		},
+		{
+			name:     "iterator() returning a non-iterator",
+			source:   "class A {\n  iterator() { return [1]; }\n}\nfor (var x in A()) print x;",
+			expected: "in: iterator() must return an instance with hasNext() and next() methods, got list.\n[line 4:12]\n",
+		},
+		{
+			name:     "range arguments",
+			source:   `range(1, "2");`,
+			expected: "): range() expects numbers, got string.\n[line 1:13]\n",
+		},
+		{
+			name:     "range step",
+			source:   "range(1, 2, 0);",
+			expected: "): range() step can't be 0.\n[line 1:14]\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source))
+		})
+	}
+}
+
+func TestInterpreterExceptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "catch runtime errors",
+			source:   "try {\n  print 1;\n  print 1 - \"a\";\n  print 2;\n} catch (e) {\n  print e.message;\n  print e.line;\n  print e.stack;\n}\nprint \"after\";",
+			expected: "1\nOperands must be numbers.\n3\n[line 3] in script\nafter\n",
+		},
+		{
+			name: "stack traces",
+			source: `
+fun a() {
+  b();
+}
+fun b() {
+  return nil.field;
+}
+try {
+  a();
+} catch (e) {
+  print e.message;
+  print e.stack;
+}`,
+			expected: "Only instances have properties.\n[line 6] in b()\n[line 3] in a()\n[line 9] in script\n",
+		},
+		{
+			name: "throw values and error objects",
+			source: `
+try {
+  throw "bad input";
+} catch (e) {
+  print e;
+}
+class ValidationError < Error {
+  init(field) {
+    super.init("invalid " + field);
+    this.field = field;
+  }
+}
+fun validate() {
+  throw ValidationError("name");
+}
+try {
+  validate();
+} catch (e) {
+  print e.message;
+  print e.field;
+  print e.line;
+  print e.stack;
+}`,
+			expected: "bad input\ninvalid name\nname\n14\n[line 14] in validate()\n[line 17] in script\n",
+		},
+		{
+			name:     "rethrown error objects keep where they were thrown",
+			source:   "var first;\ntry {\n  try {\n    throw Error(\"inner\");\n  } catch (e) {\n    first = e;\n    throw e;\n  }\n} catch (e) {\n  print e == first;\n  print e.line;\n}",
+			expected: "true\n4\n",
+		},
+		{
+			name: "finally",
+			source: `
+fun f() {
+  try {
+    return "try";
+  } finally {
+    print "finally";
+  }
+}
+print f();
+fun g() {
+  try {
+    throw "error";
+  } finally {
+    return "finally wins";
+  }
+}
+print g();
+for (var i in range(3)) {
+  try {
+    if (i == 1) continue;
+    if (i == 2) break;
+    print i;
+  } catch (e) {
+    print "unreachable";
+  } finally {
+    print "finally";
+  }
+}`,
+			expected: "finally\ntry\nfinally wins\n0\nfinally\nfinally\nfinally\n",
+		},
+		{
+			name:     "uncaught throws",
+			source:   "try {\n  throw Error(\"first\");\n} finally {\n  print \"cleanup\";\n}",
+			expected: "cleanup\nthrow: first\n[line 2:3]\n",
+		},
+		{
+			name:     "errors in catch clauses",
+			source:   "try {\n  throw 1;\n} catch (e) {\n  throw e + 1;\n}",
+			expected: "throw: 2\n[line 4:3]\n",
+		},
+		{
+			name:     "limits can't be caught",
+			source:   "fun f() { f(); }\ntry {\n  f();\n} catch (e) {\n  print \"caught\";\n} finally {\n  print \"finally\";\n}",
+			expected: "): Stack overflow.\n[line 1:13]\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source))
+		})
+	}
+}
+
+// TestInterpreterForeignValues checks that values of types the interpreter doesn't know, which the program
+// embedding it can pass, produce runtime errors rather than crashes.
+func TestInterpreterForeignValues(t *testing.T) {
+	type foreign struct{ name string }
+	var output bytes.Buffer
+	interpreter := NewInterpreter(WithOutput(&output), WithErrorOutput(&output))
+	interpreter.SetGlobal("value", foreign{name: "value"})
+	assert.Equal(t, KindObject, KindOf(foreign{}))
+	assert.Equal(t, "object", KindObject.String())
+
+	err := interpreter.Run("", "push(value, 1);")
+	require.Error(t, err)
+	assert.Equal(t, "): push() expects a list, got object.\n[line 1:14]", err.Error())
+	err = interpreter.Run("", "var m = {};\nm[value] = 1;")
+	require.Error(t, err)
+	assert.Equal(t, "]: Map key must be a string, a number, a boolean or nil, got object.\n[line 2:8]", err.Error())
+}
+
+func TestMapEntries(t *testing.T) {
+	m := NewMap([]interface{}{"a", "b"}, []interface{}{1.0, 2.0})
+	keys, values, ok := MapEntries(m)
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{"a", "b"}, keys)
+	assert.Equal(t, []interface{}{1.0, 2.0}, values)
+
+	// changing the returned keys doesn't change the map
+	keys[0] = "c"
+	keys, _, _ = MapEntries(m)
+	assert.Equal(t, []interface{}{"a", "b"}, keys)
+	assert.Equal(t, `{"a": 1, "b": 2}`, Stringify(m))
+}
+
+func TestListElements(t *testing.T) {
+	list := NewList([]interface{}{1.0, 2.0})
+	elements, ok := ListElements(list)
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{1.0, 2.0}, elements)
+
+	// changing the returned elements doesn't change the list
+	elements[0] = 3.0
+	assert.Equal(t, "[1, 2]", Stringify(list))
+}
+
+func TestInstanceFields(t *testing.T) {
+	instance := newLoxInstance(newLoxClass("A", nil, map[string]*loxFunction{}))
+	instance.fields["a"] = 1.0
+	fields, ok := InstanceFields(instance)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, fields)
+
+	// changing the returned fields doesn't change the instance
+	fields["a"] = 2.0
+	fields["b"] = 3.0
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, instance.fields)
+}
@@ -0,0 +1,152 @@
+package lox
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// iterator produces the values a for-in loop iterates over.
+type iterator interface {
+	// next returns the next value, or false once all the values are produced.
+	next(i *interpreter) (interface{}, bool, *RuntimeError)
+}
+
+// iterator returns an iterator over value, or an error located at token if value can't be iterated over.
+// Lists are iterated over by element, maps by key, strings by character and ranges by number. Instances are
+// iterated over if they implement the iterator protocol, as described by instanceIterator.
+func (i *interpreter) iterator(value interface{}, token Token) (iterator, *RuntimeError) {
+	switch value := value.(type) {
+	case *loxList:
+		return &listIterator{list: value}, nil
+	case *loxMap:
+		// the keys are copied so that the map can be changed while iterating over it
+		i.allocated += len(value.keys) * elementSize
+		keys := make([]interface{}, len(value.keys))
+		copy(keys, value.keys)
+		return &mapIterator{m: value, keys: keys}, nil
+	case string:
+		return &stringIterator{s: value}, nil
+	case loxRange:
+		return &rangeIterator{r: value}, nil
+	case *loxInstance:
+		return i.instanceIterator(value, token)
+	}
+	return nil, &RuntimeError{
+		Token:   token,
+		Message: fmt.Sprintf("Only lists, maps, strings, ranges and iterators can be iterated over, got %s.", KindOf(value)),
+	}
+}
+
+// instanceIterator returns an iterator over an instance implementing the iterator protocol. An iterator is an
+// instance with a hasNext() method, telling whether there are more values, and a next() method returning the
+// next value. An instance is iterated over if it has an iterator() method returning an iterator, or if it is
+// an iterator itself.
+func (i *interpreter) instanceIterator(instance *loxInstance, token Token) (iterator, *RuntimeError) {
+	if method, ok := instance.method("iterator"); ok {
+		result := i.callFunction(method, token, nil)
+		if err, ok := result.(*RuntimeError); ok {
+			return nil, err
+		}
+		it, ok := result.(*loxInstance)
+		if !ok {
+			return nil, &RuntimeError{
+				Token:   token,
+				Message: fmt.Sprintf("iterator() must return an instance with hasNext() and next() methods, got %s.", KindOf(result)),
+			}
+		}
+		instance = it
+	}
+	hasNext, hasNextOk := instance.method("hasNext")
+	next, nextOk := instance.method("next")
+	if !hasNextOk || !nextOk {
+		return nil, &RuntimeError{
+			Token:   token,
+			Message: fmt.Sprintf("%s must have an iterator() method or hasNext() and next() methods to be iterated over.", instance),
+		}
+	}
+	return &protocolIterator{hasNextMethod: hasNext, nextMethod: next, token: token}, nil
+}
+
+// listIterator iterates over the elements of a list. Elements added while iterating are iterated over too.
+type listIterator struct {
+	list  *loxList
+	index int
+}
+
+func (it *listIterator) next(i *interpreter) (interface{}, bool, *RuntimeError) {
+	if it.index >= len(it.list.elements) {
+		return nil, false, nil
+	}
+	it.index++
+	return it.list.elements[it.index-1], true, nil
+}
+
+// mapIterator iterates over the keys a map had when the iteration started, skipping those deleted since.
+type mapIterator struct {
+	m     *loxMap
+	keys  []interface{}
+	index int
+}
+
+func (it *mapIterator) next(i *interpreter) (interface{}, bool, *RuntimeError) {
+	for it.index < len(it.keys) {
+		key := it.keys[it.index]
+		it.index++
+		if _, ok := it.m.get(key); ok {
+			return key, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// stringIterator iterates over the characters of a string, as strings.
+type stringIterator struct {
+	s      string
+	offset int
+}
+
+func (it *stringIterator) next(i *interpreter) (interface{}, bool, *RuntimeError) {
+	if it.offset >= len(it.s) {
+		return nil, false, nil
+	}
+	_, size := utf8.DecodeRuneInString(it.s[it.offset:])
+	it.offset += size
+	return it.s[it.offset-size : it.offset], true, nil
+}
+
+// rangeIterator iterates over the numbers of a range.
+type rangeIterator struct {
+	r     loxRange
+	index int
+}
+
+func (it *rangeIterator) next(i *interpreter) (interface{}, bool, *RuntimeError) {
+	if it.index >= it.r.length() {
+		return nil, false, nil
+	}
+	it.index++
+	return it.r.start + float64(it.index-1)*it.r.step, true, nil
+}
+
+// protocolIterator iterates over an instance implementing the iterator protocol by calling its methods. Errors
+// of the calls themselves, such as a wrong arity, are located at token.
+type protocolIterator struct {
+	hasNextMethod LoxCallable
+	nextMethod    LoxCallable
+	token         Token
+}
+
+func (it *protocolIterator) next(i *interpreter) (interface{}, bool, *RuntimeError) {
+	hasNext := i.callFunction(it.hasNextMethod, it.token, nil)
+	if err, ok := hasNext.(*RuntimeError); ok {
+		return nil, false, err
+	}
+	if !isTruthy(hasNext) {
+		return nil, false, nil
+	}
+	value := i.callFunction(it.nextMethod, it.token, nil)
+	if err, ok := value.(*RuntimeError); ok {
+		return nil, false, err
+	}
+	return value, true, nil
+}
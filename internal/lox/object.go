@@ -0,0 +1,149 @@
+package lox
+
+import "fmt"
+
+// obj is a heap-allocated value of the bytecode virtual machine.
+type obj interface {
+	String() string
+	header() *objHeader
+}
+
+// objHeader holds the state the garbage collector keeps for every object allocated by the virtual
+// machine. Objects created by the compiler are owned by the compiled function and are never collected.
+type objHeader struct {
+	isMarked bool
+	// nextObject is the next object in the list of all the objects allocated by the virtual machine.
+	nextObject obj
+}
+
+func (h *objHeader) header() *objHeader {
+	return h
+}
+
+type objString struct {
+	objHeader
+	chars string
+}
+
+func (o *objString) String() string {
+	return o.chars
+}
+
+// objFunction is a compiled function. It is created by the compiler and never changes at runtime.
+type objFunction struct {
+	objHeader
+	arity        int
+	upvalueCount int
+	chunk        *chunk
+	// name is empty for the top-level script.
+	name string
+}
+
+// Function is the type of a compiled function outside of this package, such as the top-level function
+// of a script returned by the compiler.
+type Function = objFunction
+
+func newObjFunction(name string) *objFunction {
+	return &objFunction{
+		chunk: newChunk(),
+		name:  name,
+	}
+}
+
+func (o *objFunction) String() string {
+	if o.name == "" {
+		return "<script>"
+	}
+	return fmt.Sprintf("<fn %s>", o.name)
+}
+
+type nativeFn func(vm *vm, arguments []value) (value, error)
+
+type objNative struct {
+	objHeader
+	name     string
+	arity    int
+	function nativeFn
+}
+
+func (o *objNative) String() string {
+	return "<native fn>"
+}
+
+// objUpvalue is a variable captured by a closure. While it is open, the variable lives on the stack at
+// index slot. Once the variable goes out of scope, the upvalue is closed and the value moves into it.
+type objUpvalue struct {
+	objHeader
+	slot     int
+	closed   value
+	isClosed bool
+	// next is the next open upvalue, sorted by decreasing stack slot.
+	next *objUpvalue
+}
+
+func (o *objUpvalue) String() string {
+	return "upvalue"
+}
+
+// objClosure is a function along with the variables it captures.
+type objClosure struct {
+	objHeader
+	function *objFunction
+	upvalues []*objUpvalue
+}
+
+func newObjClosure(function *objFunction) *objClosure {
+	return &objClosure{
+		function: function,
+		upvalues: make([]*objUpvalue, function.upvalueCount),
+	}
+}
+
+func (o *objClosure) String() string {
+	return o.function.String()
+}
+
+type objClass struct {
+	objHeader
+	name    string
+	methods map[string]*objClosure
+}
+
+func newObjClass(name string) *objClass {
+	return &objClass{
+		name:    name,
+		methods: make(map[string]*objClosure),
+	}
+}
+
+func (o *objClass) String() string {
+	return o.name
+}
+
+type objInstance struct {
+	objHeader
+	class  *objClass
+	fields map[string]value
+}
+
+func newObjInstance(class *objClass) *objInstance {
+	return &objInstance{
+		class:  class,
+		fields: make(map[string]value),
+	}
+}
+
+func (o *objInstance) String() string {
+	return fmt.Sprintf("%s instance", o.class.name)
+}
+
+// objBoundMethod is a method along with the instance it was accessed from.
+type objBoundMethod struct {
+	objHeader
+	receiver value
+	method   *objClosure
+}
+
+func (o *objBoundMethod) String() string {
+	return o.method.String()
+}
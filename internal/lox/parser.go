@@ -2,40 +2,72 @@ package lox
 
 import (
 	"fmt"
+
+	"github.com/nockty/glox/internal/diagnostics"
 )
 
+// maxArguments is the maximum number of arguments a call can have, and therefore the maximum number of
+// parameters of a function.
+const maxArguments = 255
+
 type parser struct {
 	tokens  []Token
 	current int
+	// loopDepth is the number of loops enclosing the current statement in the current function, where break
+	// and continue statements are allowed.
+	loopDepth int
 
-	errors []*parseError
+	errors []*ParseError
 }
 
 // NewParser creates a parser for the lox language. The complete expression grammar is the following:
 //
 // program     → declaration* EOF ;
 //
-// declaration → varDecl | statement ;
+// declaration → classDecl | funDecl | varDecl | statement ;
+//
+// classDecl   → "class" IDENTIFIER ( "<" IDENTIFIER )? "{" function* "}" ;
+//
+// funDecl     → "fun" function ;
+//
+// function    → IDENTIFIER "(" parameters? ")" block ;
+//
+// parameters  → IDENTIFIER ( "," IDENTIFIER )* ;
 //
 // varDecl     → "var" IDENTIFIER ( "=" expression )? ";" ;
 //
-// statement   → exprStmt | forStmt | ifStmt | printStmt | whileStmt | block ;
+// statement   → exprStmt | forStmt | forInStmt | ifStmt | printStmt | returnStmt | whileStmt | breakStmt | continueStmt | throwStmt | tryStmt | block ;
 //
 // exprStmt    → expression ";" ;
 //
 // forStmt     → "for" "(" ( varDecl | exprStmt | ";" ) expression? ";" expression? ")" statement ;
 //
+// forInStmt   → "for" "(" "var" IDENTIFIER "in" expression ")" statement ;
+//
 // ifStmt      → "if" "(" expression ")" statement ( "else" statement )? ;
 //
 // printStmt   → "print" expression ";" ;
 //
+// returnStmt  → "return" expression? ";" ;
+//
 // whileStmt   → "while" "(" expression ")" statement ;
 //
+// breakStmt   → "break" ";" ;
+//
+// continueStmt → "continue" ";" ;
+//
+// throwStmt   → "throw" expression ";" ;
+//
+// tryStmt     → "try" block ( "catch" "(" IDENTIFIER ")" block ( "finally" block )? | "finally" block ) ;
+//
 // block       → "{" declaration* "}" ;
 //
+// A statement starting with "{" followed by a token and ":" is an expression statement starting with a map
+// literal rather than a block.
+//
 // expression  → assignment ;
 //
-// assignment  → IDENTIFIER "=" assignment | logic_or ;
+// assignment  → ( call "." )? IDENTIFIER "=" assignment | call "[" expression "]" "=" assignment | logic_or ;
 //
 // logic_or    → logic_and ( "or" logic_and )* ;
 //
@@ -49,14 +81,22 @@ type parser struct {
 //
 // factor      → unary ( ( "/" | "*" ) unary )* ;
 //
-// unary       → ( "!" | "-" ) unary | primary ;
+// unary       → ( "!" | "-" ) unary | call ;
+//
+// call        → primary ( "(" arguments? ")" | "." IDENTIFIER | "[" index "]" )* ;
+//
+// arguments   → expression ( "," expression )* ;
 //
-// primary     → IDENTIFIER | NUMBER | STRING | "true" | "false" | "nil" | "(" expression ")" ;
+// index       → expression | expression? ":" expression? ;
+//
+// primary     → IDENTIFIER | NUMBER | STRING | "true" | "false" | "nil" | "this" | "(" expression ")" | "super" "." IDENTIFIER | "[" arguments? "]" | "{" entries? "}" ;
+//
+// entries     → expression ":" expression ( "," expression ":" expression )* ;
 func NewParser(tokens []Token) *parser {
 	return &parser{
 		tokens:  tokens,
 		current: 0,
-		errors:  make([]*parseError, 0),
+		errors:  make([]*ParseError, 0),
 	}
 }
 
@@ -69,20 +109,41 @@ func (p *parser) Parse() []Stmt {
 	return statements
 }
 
-// TODO change this to see how we want to handle errors
+// ParseExpression parses source made of a single expression.
+func (p *parser) ParseExpression() Expr {
+	expr, err := p.expression()
+	if err == nil && !p.isAtEnd() {
+		err = p.error(p.peek(), "Expect end of expression.")
+	}
+	if err != nil {
+		p.errors = append(p.errors, err)
+		return nil
+	}
+	return expr
+}
+
+// HadErrors reports whether there were errors while parsing.
 func (p *parser) HadErrors() bool {
-	hadErrors := false
+	return len(p.errors) > 0
+}
+
+// Errors returns the errors found while parsing, as *ParseError.
+func (p *parser) Errors() []error {
+	result := make([]error, 0, len(p.errors))
 	for _, err := range p.errors {
-		println(err.Error())
-		hadErrors = true
+		result = append(result, err)
 	}
-	return hadErrors
+	return result
 }
 
 func (p *parser) declaration() Stmt {
 	var statement Stmt
-	var err *parseError
-	if p.match(Var) {
+	var err *ParseError
+	if p.match(Class) {
+		statement, err = p.classDeclaration()
+	} else if p.match(Fun) {
+		statement, err = p.function("function")
+	} else if p.match(Var) {
 		statement, err = p.varDeclaration()
 	} else {
 		statement, err = p.statement()
@@ -95,7 +156,97 @@ func (p *parser) declaration() Stmt {
 	return statement
 }
 
-func (p *parser) varDeclaration() (Stmt, *parseError) {
+func (p *parser) classDeclaration() (Stmt, *ParseError) {
+	start := p.previous()
+	name, err := p.consume(Identifier, "Expect class name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var superclass *VariableExpr = nil
+	if p.match(Less) {
+		superclassName, err := p.consume(Identifier, "Expect superclass name.")
+		if err != nil {
+			return nil, err
+		}
+		superclass = NewVariableExpr(superclassName)
+		superclass.setSpan(superclassName.Span())
+	}
+
+	_, err = p.consume(LeftBrace, "Expect '{' before class body.")
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]*FunctionStmt, 0)
+	for !p.check(RightBrace) && !p.isAtEnd() {
+		method, err := p.function("method")
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+
+	_, err = p.consume(RightBrace, "Expect '}' after class body.")
+	if err != nil {
+		return nil, err
+	}
+	return p.spanStmt(NewClassStmt(name, superclass, methods), start), nil
+}
+
+// function parses a named function. kind is used in error messages to describe what is being parsed.
+func (p *parser) function(kind string) (*FunctionStmt, *ParseError) {
+	start := p.peek()
+	name, err := p.consume(Identifier, fmt.Sprintf("Expect %s name.", kind))
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(LeftParen, fmt.Sprintf("Expect '(' after %s name.", kind))
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := make([]Token, 0)
+	if !p.check(RightParen) {
+		for {
+			if len(parameters) >= maxArguments {
+				// Report the error without entering panic mode: the parser is not confused.
+				p.errors = append(p.errors, p.error(p.peek(), fmt.Sprintf("Can't have more than %d parameters.", maxArguments)))
+			}
+			parameter, err := p.consume(Identifier, "Expect parameter name.")
+			if err != nil {
+				return nil, err
+			}
+			parameters = append(parameters, parameter)
+			if !p.match(Comma) {
+				break
+			}
+		}
+	}
+	_, err = p.consume(RightParen, "Expect ')' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(LeftBrace, fmt.Sprintf("Expect '{' before %s body.", kind))
+	if err != nil {
+		return nil, err
+	}
+	// loops don't extend into the functions declared in them
+	enclosingLoopDepth := p.loopDepth
+	p.loopDepth = 0
+	body, err := p.block()
+	p.loopDepth = enclosingLoopDepth
+	if err != nil {
+		return nil, err
+	}
+	function := NewFunctionStmt(name, parameters, body)
+	function.setSpan(p.spanFrom(start))
+	return function, nil
+}
+
+func (p *parser) varDeclaration() (Stmt, *ParseError) {
+	start := p.previous()
 	name, err := p.consume(Identifier, "Expect variable name.")
 	if err != nil {
 		return nil, err
@@ -113,10 +264,10 @@ func (p *parser) varDeclaration() (Stmt, *parseError) {
 	if err != nil {
 		return nil, err
 	}
-	return NewVarStmt(name, initializer), nil
+	return p.spanStmt(NewVarStmt(name, initializer), start), nil
 }
 
-func (p *parser) statement() (Stmt, *parseError) {
+func (p *parser) statement() (Stmt, *ParseError) {
 	if p.match(For) {
 		return p.forStatement()
 	}
@@ -126,24 +277,48 @@ func (p *parser) statement() (Stmt, *parseError) {
 	if p.match(Print) {
 		return p.printStatement()
 	}
+	if p.match(Return) {
+		return p.returnStatement()
+	}
 	if p.match(While) {
 		return p.whileStatement()
 	}
-	if p.match(LeftBrace) {
+	if p.match(Throw) {
+		return p.throwStatement()
+	}
+	if p.match(Try) {
+		return p.tryStatement()
+	}
+	if p.match(Break, Continue) {
+		return p.loopControlStatement()
+	}
+	if !p.startsMapLiteral() && p.match(LeftBrace) {
+		start := p.previous()
 		statements, err := p.block()
 		if err != nil {
 			return nil, err
 		}
-		return NewBlockStmt(statements), nil
+		return p.spanStmt(NewBlockStmt(statements), start), nil
 	}
 	return p.expressionStatement()
 }
 
-func (p *parser) forStatement() (Stmt, *parseError) {
+// startsMapLiteral reports whether the next tokens start a map literal rather than a block, which both start
+// with "{". A statement starting with "{" is a map literal only if its first key is a single token followed
+// by ":", which can't start a block.
+func (p *parser) startsMapLiteral() bool {
+	return p.check(LeftBrace) && p.checkAhead(2, Colon)
+}
+
+func (p *parser) forStatement() (Stmt, *ParseError) {
+	start := p.previous()
 	_, err := p.consume(LeftParen, "Expect '(' after 'for'.")
 	if err != nil {
 		return nil, err
 	}
+	if p.check(Var) && p.checkAhead(1, Identifier) && p.checkAhead(2, In) {
+		return p.forInStatement(start)
+	}
 
 	var initializer Stmt
 	if p.match(Semicolon) {
@@ -186,34 +361,60 @@ func (p *parser) forStatement() (Stmt, *parseError) {
 		return nil, err
 	}
 
+	p.loopDepth++
 	body, err := p.statement()
+	p.loopDepth--
 	if err != nil {
 		return nil, err
 	}
 
-	if increment != nil {
-		body = NewBlockStmt([]Stmt{
-			body,
-			NewExpressionStmt(increment),
-		})
-	}
-
+	// the nodes introduced by the desugaring span the whole loop. The increment is kept apart from the body
+	// so that it also runs after a continue statement.
+	span := p.spanFrom(start)
 	if condition == nil {
 		condition = NewLiteralExpr(true)
+		condition.setSpan(span)
 	}
-	body = NewWhileStmt(condition, body)
+	body = NewWhileStmt(start, condition, body, increment)
+	body.setSpan(span)
 
 	if initializer != nil {
 		body = NewBlockStmt([]Stmt{
 			initializer,
 			body,
 		})
+		body.setSpan(span)
 	}
 
 	return body, nil
 }
 
-func (p *parser) ifStatement() (Stmt, *parseError) {
+// forInStatement parses the rest of a for-in loop, whose "for" and "(" tokens are consumed. The loop isn't
+// desugared: how it iterates depends on the value it iterates over, which is only known at runtime.
+func (p *parser) forInStatement(start Token) (Stmt, *ParseError) {
+	p.advance() // var
+	name := p.advance()
+	in := p.advance()
+	iterable, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(RightParen, "Expect ')' after for-in clause.")
+	if err != nil {
+		return nil, err
+	}
+
+	p.loopDepth++
+	body, err := p.statement()
+	p.loopDepth--
+	if err != nil {
+		return nil, err
+	}
+	return p.spanStmt(NewForInStmt(start, name, in, iterable, body), start), nil
+}
+
+func (p *parser) ifStatement() (Stmt, *ParseError) {
+	start := p.previous()
 	_, err := p.consume(LeftParen, "Expect '(' after 'if'.")
 	if err != nil {
 		return nil, err
@@ -239,10 +440,11 @@ func (p *parser) ifStatement() (Stmt, *parseError) {
 		}
 	}
 
-	return NewIfStmt(condition, thenBranch, elseBranch), nil
+	return p.spanStmt(NewIfStmt(condition, thenBranch, elseBranch), start), nil
 }
 
-func (p *parser) printStatement() (Stmt, *parseError) {
+func (p *parser) printStatement() (Stmt, *ParseError) {
+	start := p.previous()
 	value, err := p.expression()
 	if err != nil {
 		return nil, err
@@ -251,10 +453,97 @@ func (p *parser) printStatement() (Stmt, *parseError) {
 	if err != nil {
 		return nil, err
 	}
-	return NewPrintStmt(value), nil
+	return p.spanStmt(NewPrintStmt(value), start), nil
+}
+
+func (p *parser) returnStatement() (Stmt, *ParseError) {
+	keyword := p.previous()
+	var value Expr = nil
+	if !p.check(Semicolon) {
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		value = expr
+	}
+	_, err := p.consume(Semicolon, "Expect ';' after return value.")
+	if err != nil {
+		return nil, err
+	}
+	return p.spanStmt(NewReturnStmt(keyword, value), keyword), nil
+}
+
+func (p *parser) throwStatement() (Stmt, *ParseError) {
+	keyword := p.previous()
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(Semicolon, "Expect ';' after thrown value.")
+	if err != nil {
+		return nil, err
+	}
+	return p.spanStmt(NewThrowStmt(keyword, value), keyword), nil
+}
+
+// tryStatement parses a try statement. The body of a missing catch or finally clause is nil.
+func (p *parser) tryStatement() (Stmt, *ParseError) {
+	keyword := p.previous()
+	_, err := p.consume(LeftBrace, "Expect '{' after 'try'.")
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	var catchName Token
+	var catchBody []Stmt
+	if p.match(Catch) {
+		_, err = p.consume(LeftParen, "Expect '(' after 'catch'.")
+		if err != nil {
+			return nil, err
+		}
+		catchName, err = p.consume(Identifier, "Expect error variable name.")
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(RightParen, "Expect ')' after error variable name.")
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(LeftBrace, "Expect '{' before catch body.")
+		if err != nil {
+			return nil, err
+		}
+		catchBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var finallyBody []Stmt
+	if p.match(Finally) {
+		_, err = p.consume(LeftBrace, "Expect '{' after 'finally'.")
+		if err != nil {
+			return nil, err
+		}
+		finallyBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if catchBody == nil && finallyBody == nil {
+		// Report the error without entering panic mode: the parser is not confused.
+		p.errors = append(p.errors, p.error(p.previous(), "Expect 'catch' or 'finally' after try block."))
+	}
+	return p.spanStmt(NewTryStmt(keyword, body, catchName, catchBody, finallyBody), keyword), nil
 }
 
-func (p *parser) whileStatement() (Stmt, *parseError) {
+func (p *parser) whileStatement() (Stmt, *ParseError) {
+	start := p.previous()
 	_, err := p.consume(LeftParen, "Expect '(' after 'while'.")
 	if err != nil {
 		return nil, err
@@ -267,15 +556,35 @@ func (p *parser) whileStatement() (Stmt, *parseError) {
 	if err != nil {
 		return nil, err
 	}
+	p.loopDepth++
 	body, err := p.statement()
+	p.loopDepth--
 	if err != nil {
 		return nil, err
 	}
 
-	return NewWhileStmt(condition, body), nil
+	return p.spanStmt(NewWhileStmt(start, condition, body, nil), start), nil
 }
 
-func (p *parser) expressionStatement() (Stmt, *parseError) {
+// loopControlStatement parses a break or continue statement, whose keyword was just matched.
+func (p *parser) loopControlStatement() (Stmt, *ParseError) {
+	keyword := p.previous()
+	if p.loopDepth == 0 {
+		// Report the error without entering panic mode: the parser is not confused.
+		p.errors = append(p.errors, p.error(keyword, fmt.Sprintf("Can't use '%s' outside of a loop.", keyword.Lexeme)))
+	}
+	_, err := p.consume(Semicolon, fmt.Sprintf("Expect ';' after '%s'.", keyword.Lexeme))
+	if err != nil {
+		return nil, err
+	}
+	if keyword.Type == Break {
+		return p.spanStmt(NewBreakStmt(keyword), keyword), nil
+	}
+	return p.spanStmt(NewContinueStmt(keyword), keyword), nil
+}
+
+func (p *parser) expressionStatement() (Stmt, *ParseError) {
+	start := p.peek()
 	value, err := p.expression()
 	if err != nil {
 		return nil, err
@@ -284,10 +593,10 @@ func (p *parser) expressionStatement() (Stmt, *parseError) {
 	if err != nil {
 		return nil, err
 	}
-	return NewExpressionStmt(value), nil
+	return p.spanStmt(NewExpressionStmt(value), start), nil
 }
 
-func (p *parser) block() ([]Stmt, *parseError) {
+func (p *parser) block() ([]Stmt, *ParseError) {
 	statements := make([]Stmt, 0)
 
 	for !p.check(RightBrace) && !p.isAtEnd() {
@@ -301,11 +610,12 @@ func (p *parser) block() ([]Stmt, *parseError) {
 	return statements, nil
 }
 
-func (p *parser) expression() (Expr, *parseError) {
+func (p *parser) expression() (Expr, *ParseError) {
 	return p.assignment()
 }
 
-func (p *parser) assignment() (Expr, *parseError) {
+func (p *parser) assignment() (Expr, *ParseError) {
+	start := p.peek()
 	expr, err := p.or()
 	if err != nil {
 		return nil, err
@@ -317,19 +627,26 @@ func (p *parser) assignment() (Expr, *parseError) {
 		if err != nil {
 			return nil, err
 		}
-		variable, ok := expr.(*VariableExpr)
-		if ok {
-			return NewAssignExpr(variable.name, value), nil
+		switch target := expr.(type) {
+		case *VariableExpr:
+			return p.spanExpr(NewAssignExpr(target.name, value), start), nil
+		case *GetExpr:
+			return p.spanExpr(NewSetExpr(target.object, target.name, value), start), nil
+		case *IndexExpr:
+			return p.spanExpr(NewIndexSetExpr(target.object, target.bracket, target.index, value), start), nil
 		}
 		// Add error but don't return it because the parser isn’t in a confused state where we need to go
 		// into panic mode and synchronize.
-		p.errors = append(p.errors, p.error(equals, "Invalid assignment target."))
+		targetErr := p.error(equals, "Invalid assignment target.")
+		targetErr.Help = "only variables, fields and list elements can be assigned to"
+		p.errors = append(p.errors, targetErr)
 	}
 
 	return expr, nil
 }
 
-func (p *parser) or() (Expr, *parseError) {
+func (p *parser) or() (Expr, *ParseError) {
+	start := p.peek()
 	expr, err := p.and()
 	if err != nil {
 		return nil, err
@@ -341,13 +658,14 @@ func (p *parser) or() (Expr, *parseError) {
 		if err != nil {
 			return nil, err
 		}
-		expr = NewLogicalExpr(expr, operator, right)
+		expr = p.spanExpr(NewLogicalExpr(expr, operator, right), start)
 	}
 
 	return expr, nil
 }
 
-func (p *parser) and() (Expr, *parseError) {
+func (p *parser) and() (Expr, *ParseError) {
+	start := p.peek()
 	expr, err := p.equality()
 	if err != nil {
 		return nil, err
@@ -359,13 +677,14 @@ func (p *parser) and() (Expr, *parseError) {
 		if err != nil {
 			return nil, err
 		}
-		expr = NewLogicalExpr(expr, operator, right)
+		expr = p.spanExpr(NewLogicalExpr(expr, operator, right), start)
 	}
 
 	return expr, nil
 }
 
-func (p *parser) equality() (Expr, *parseError) {
+func (p *parser) equality() (Expr, *ParseError) {
+	start := p.peek()
 	expr, err := p.comparison()
 	if err != nil {
 		return nil, err
@@ -377,13 +696,14 @@ func (p *parser) equality() (Expr, *parseError) {
 		if err != nil {
 			return nil, err
 		}
-		expr = NewBinaryExpr(expr, operator, right)
+		expr = p.spanExpr(NewBinaryExpr(expr, operator, right), start)
 	}
 
 	return expr, nil
 }
 
-func (p *parser) comparison() (Expr, *parseError) {
+func (p *parser) comparison() (Expr, *ParseError) {
+	start := p.peek()
 	expr, err := p.term()
 	if err != nil {
 		return nil, err
@@ -395,13 +715,14 @@ func (p *parser) comparison() (Expr, *parseError) {
 		if err != nil {
 			return nil, err
 		}
-		expr = NewBinaryExpr(expr, operator, right)
+		expr = p.spanExpr(NewBinaryExpr(expr, operator, right), start)
 	}
 
 	return expr, nil
 }
 
-func (p *parser) term() (Expr, *parseError) {
+func (p *parser) term() (Expr, *ParseError) {
+	start := p.peek()
 	expr, err := p.factor()
 	if err != nil {
 		return nil, err
@@ -413,13 +734,14 @@ func (p *parser) term() (Expr, *parseError) {
 		if err != nil {
 			return nil, err
 		}
-		expr = NewBinaryExpr(expr, operator, right)
+		expr = p.spanExpr(NewBinaryExpr(expr, operator, right), start)
 	}
 
 	return expr, nil
 }
 
-func (p *parser) factor() (Expr, *parseError) {
+func (p *parser) factor() (Expr, *ParseError) {
+	start := p.peek()
 	expr, err := p.unary()
 	if err != nil {
 		return nil, err
@@ -431,48 +753,155 @@ func (p *parser) factor() (Expr, *parseError) {
 		if err != nil {
 			return nil, err
 		}
-		expr = NewBinaryExpr(expr, operator, right)
+		expr = p.spanExpr(NewBinaryExpr(expr, operator, right), start)
 	}
 
 	return expr, nil
 }
 
-func (p *parser) unary() (Expr, *parseError) {
+func (p *parser) unary() (Expr, *ParseError) {
 	if p.match(Bang, Minus) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
 			return nil, err
 		}
-		return NewUnaryExpr(operator, right), nil
+		return p.spanExpr(NewUnaryExpr(operator, right), operator), nil
 	}
+	expr, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+func (p *parser) call() (Expr, *ParseError) {
+	start := p.peek()
 	expr, err := p.primary()
 	if err != nil {
 		return nil, err
 	}
+
+	for {
+		if p.match(LeftParen) {
+			expr, err = p.finishCall(expr)
+			if err != nil {
+				return nil, err
+			}
+			expr = p.spanExpr(expr, start)
+		} else if p.match(Dot) {
+			name, err := p.consume(Identifier, "Expect property name after '.'.")
+			if err != nil {
+				return nil, err
+			}
+			expr = p.spanExpr(NewGetExpr(expr, name), start)
+		} else if p.match(LeftBracket) {
+			expr, err = p.finishIndex(expr)
+			if err != nil {
+				return nil, err
+			}
+			expr = p.spanExpr(expr, start)
+		} else {
+			break
+		}
+	}
+
 	return expr, nil
 }
 
-func (p *parser) primary() (Expr, *parseError) {
+func (p *parser) finishCall(callee Expr) (Expr, *ParseError) {
+	arguments := make([]Expr, 0)
+	if !p.check(RightParen) {
+		for {
+			if len(arguments) >= maxArguments {
+				// Report the error without entering panic mode: the parser is not confused.
+				p.errors = append(p.errors, p.error(p.peek(), fmt.Sprintf("Can't have more than %d arguments.", maxArguments)))
+			}
+			argument, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, argument)
+			if !p.match(Comma) {
+				break
+			}
+		}
+	}
+	paren, err := p.consume(RightParen, "Expect ')' after arguments.")
+	if err != nil {
+		return nil, err
+	}
+	return NewCallExpr(callee, paren, arguments), nil
+}
+
+// finishIndex parses an index or a slice of object, whose opening bracket was just matched.
+func (p *parser) finishIndex(object Expr) (Expr, *ParseError) {
+	var start, end Expr
+	var err *ParseError
+	if !p.check(Colon) {
+		start, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !p.match(Colon) {
+		bracket, err := p.consume(RightBracket, "Expect ']' after index.")
+		if err != nil {
+			return nil, err
+		}
+		return NewIndexExpr(object, bracket, start), nil
+	}
+	if !p.check(RightBracket) {
+		end, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	bracket, err := p.consume(RightBracket, "Expect ']' after slice.")
+	if err != nil {
+		return nil, err
+	}
+	return NewSliceExpr(object, bracket, start, end), nil
+}
+
+func (p *parser) primary() (Expr, *ParseError) {
 	if p.match(False) {
-		return NewLiteralExpr(false), nil
+		return p.spanExpr(NewLiteralExpr(false), p.previous()), nil
 	}
 	if p.match(True) {
-		return NewLiteralExpr(true), nil
+		return p.spanExpr(NewLiteralExpr(true), p.previous()), nil
 	}
 	if p.match(Nil) {
-		return NewLiteralExpr(nil), nil
+		return p.spanExpr(NewLiteralExpr(nil), p.previous()), nil
 	}
 
 	if p.match(Number, String) {
-		return NewLiteralExpr(p.previous().Literal), nil
+		return p.spanExpr(NewLiteralExpr(p.previous().Literal), p.previous()), nil
+	}
+
+	if p.match(Super) {
+		keyword := p.previous()
+		_, err := p.consume(Dot, "Expect '.' after 'super'.")
+		if err != nil {
+			return nil, err
+		}
+		method, err := p.consume(Identifier, "Expect superclass method name.")
+		if err != nil {
+			return nil, err
+		}
+		return p.spanExpr(NewSuperExpr(keyword, method), keyword), nil
+	}
+
+	if p.match(This) {
+		return p.spanExpr(NewThisExpr(p.previous()), p.previous()), nil
 	}
 
 	if p.match(Identifier) {
-		return NewVariableExpr(p.previous()), nil
+		return p.spanExpr(NewVariableExpr(p.previous()), p.previous()), nil
 	}
 
 	if p.match(LeftParen) {
+		start := p.previous()
 		expr, err := p.expression()
 		if err != nil {
 			return nil, err
@@ -481,13 +910,67 @@ func (p *parser) primary() (Expr, *parseError) {
 		if err != nil {
 			return nil, err
 		}
-		return NewGroupingExpr(expr), nil
+		return p.spanExpr(NewGroupingExpr(expr), start), nil
+	}
+
+	if p.match(LeftBracket) {
+		bracket := p.previous()
+		elements := make([]Expr, 0)
+		if !p.check(RightBracket) {
+			for {
+				element, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+				if !p.match(Comma) {
+					break
+				}
+			}
+		}
+		_, err := p.consume(RightBracket, "Expect ']' after list elements.")
+		if err != nil {
+			return nil, err
+		}
+		return p.spanExpr(NewListExpr(bracket, elements), bracket), nil
+	}
+
+	if p.match(LeftBrace) {
+		brace := p.previous()
+		keys := make([]Expr, 0)
+		values := make([]Expr, 0)
+		if !p.check(RightBrace) {
+			for {
+				key, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+				_, err = p.consume(Colon, "Expect ':' after map key.")
+				if err != nil {
+					return nil, err
+				}
+				value, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+				keys = append(keys, key)
+				values = append(values, value)
+				if !p.match(Comma) {
+					break
+				}
+			}
+		}
+		_, err := p.consume(RightBrace, "Expect '}' after map entries.")
+		if err != nil {
+			return nil, err
+		}
+		return p.spanExpr(NewMapExpr(brace, keys, values), brace), nil
 	}
 
 	return nil, p.error(p.peek(), "Expect expression.")
 }
 
-func (p *parser) consume(t TokenType, message string) (Token, *parseError) {
+func (p *parser) consume(t TokenType, message string) (Token, *ParseError) {
 	if p.check(t) {
 		return p.advance(), nil
 	}
@@ -512,6 +995,14 @@ func (p *parser) check(t TokenType) bool {
 	return p.peek().Type == t
 }
 
+// checkAhead is like check, for the token offset tokens after the current one.
+func (p *parser) checkAhead(offset int, t TokenType) bool {
+	if p.current+offset >= len(p.tokens) {
+		return false
+	}
+	return p.tokens[p.current+offset].Type == t
+}
+
 func (p *parser) advance() Token {
 	if !p.isAtEnd() {
 		p.current++
@@ -531,12 +1022,29 @@ func (p *parser) previous() Token {
 	return p.tokens[p.current-1]
 }
 
-func (p *parser) error(token Token, message string) *parseError {
+// spanFrom returns the span going from start to the last consumed token.
+func (p *parser) spanFrom(start Token) Span {
+	return start.Span().To(p.previous().Span())
+}
+
+// spanExpr sets the span of expr from start to the last consumed token, and returns expr.
+func (p *parser) spanExpr(expr Expr, start Token) Expr {
+	expr.setSpan(p.spanFrom(start))
+	return expr
+}
+
+// spanStmt sets the span of stmt from start to the last consumed token, and returns stmt.
+func (p *parser) spanStmt(stmt Stmt, start Token) Stmt {
+	stmt.setSpan(p.spanFrom(start))
+	return stmt
+}
+
+func (p *parser) error(token Token, message string) *ParseError {
 	where := "at end"
 	if token.Type != EOF {
 		where = fmt.Sprintf("at '%s'", token.Lexeme)
 	}
-	return &parseError{line: token.Line, where: where, message: message}
+	return &ParseError{Span: token.Span(), Where: where, Message: message}
 }
 
 func (p *parser) synchronize() {
@@ -547,19 +1055,34 @@ func (p *parser) synchronize() {
 			return
 		}
 		switch p.peek().Type {
-		case Class, Fun, Var, For, If, While, Print, Return:
+		case Class, Fun, Var, For, If, While, Print, Return, Break, Continue, Throw, Try:
 			return
 		}
 		p.advance()
 	}
 }
 
-type parseError struct {
-	line    int
-	where   string
-	message string
+// ParseError is an error in the syntactic grammar.
+type ParseError struct {
+	// Span locates the token where the error was found. Its fields, such as Line, are promoted.
+	Span
+	// Where describes the location for humans, e.g. "at ';'" or "at end".
+	Where   string
+	Message string
+	// Help suggests a fix, if any.
+	Help string
 }
 
-func (e *parseError) Error() string {
-	return fmt.Sprintf("[line %d] Error %s: %s", e.line, e.where, e.message)
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("[%s] Error %s: %s", e.Span, e.Where, e.Message)
+}
+
+func (e *ParseError) diagnostic() diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Severity: diagnostics.Error,
+		Code:     "parse-error",
+		Message:  e.Message,
+		Span:     e.Span,
+		Help:     e.Help,
+	}
 }
@@ -0,0 +1,442 @@
+package lox
+
+import (
+	"fmt"
+
+	"github.com/nockty/glox/internal/diagnostics"
+)
+
+type functionType int
+
+const (
+	functionTypeNone functionType = iota
+	functionTypeFunction
+	functionTypeInitializer
+	functionTypeMethod
+)
+
+type classType int
+
+const (
+	classTypeNone classType = iota
+	classTypeClass
+	classTypeSubclass
+)
+
+// resolver is a static pass run between the parser and the interpreter. It computes how many scopes away
+// each local variable is declared and hands that distance to the interpreter, and it reports static
+// errors such as reading a local variable in its own initializer.
+type resolver struct {
+	interpreter *interpreter
+	// scopes is a stack of the block scopes currently in scope. Global variables are not tracked. For each
+	// variable, the value tells whether its initializer has been resolved yet.
+	scopes          []map[string]bool
+	currentFunction functionType
+	currentClass    classType
+
+	errors []*ResolveError
+}
+
+// resolver implements visitorExpr and visitorStmt
+var _ visitorExpr = &resolver{}
+var _ visitorStmt = &resolver{}
+
+func NewResolver(i *interpreter) *resolver {
+	return &resolver{
+		interpreter:     i,
+		scopes:          make([]map[string]bool, 0),
+		currentFunction: functionTypeNone,
+		currentClass:    classTypeNone,
+		errors:          make([]*ResolveError, 0),
+	}
+}
+
+func (r *resolver) Resolve(statements []Stmt) {
+	r.resolveStmts(statements)
+}
+
+// HadErrors reports whether there were errors while resolving.
+func (r *resolver) HadErrors() bool {
+	return len(r.errors) > 0
+}
+
+// Errors returns the errors found while resolving, as *ResolveError.
+func (r *resolver) Errors() []error {
+	result := make([]error, 0, len(r.errors))
+	for _, err := range r.errors {
+		result = append(result, err)
+	}
+	return result
+}
+
+func (r *resolver) resolveStmts(statements []Stmt) {
+	for _, statement := range statements {
+		r.resolveStmt(statement)
+	}
+}
+
+func (r *resolver) resolveStmt(stmt Stmt) {
+	stmt.Accept(r)
+}
+
+func (r *resolver) resolveExpr(expr Expr) {
+	expr.Accept(r)
+}
+
+func (r *resolver) resolveFunction(function *FunctionStmt, functionType functionType) {
+	enclosingFunction := r.currentFunction
+	r.currentFunction = functionType
+	defer func() { r.currentFunction = enclosingFunction }()
+
+	r.beginScope()
+	for _, param := range function.params {
+		r.declare(param)
+		r.define(param)
+	}
+	r.resolveStmts(function.body)
+	r.endScope()
+}
+
+// resolveLocal tells the interpreter how many scopes away from the innermost one the variable is
+// declared. Variables that are not found are assumed to be global.
+func (r *resolver) resolveLocal(expr Expr, name Token) {
+	for depth := len(r.scopes) - 1; depth >= 0; depth-- {
+		if _, ok := r.scopes[depth][name.Lexeme]; ok {
+			r.interpreter.resolve(expr, len(r.scopes)-1-depth)
+			return
+		}
+	}
+}
+
+func (r *resolver) beginScope() {
+	r.scopes = append(r.scopes, make(map[string]bool))
+}
+
+func (r *resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *resolver) declare(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+	scope := r.scopes[len(r.scopes)-1]
+	if _, ok := scope[name.Lexeme]; ok {
+		r.error(name, "Already a variable with this name in this scope.")
+	}
+	scope[name.Lexeme] = false
+}
+
+func (r *resolver) define(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+	r.scopes[len(r.scopes)-1][name.Lexeme] = true
+}
+
+func (r *resolver) error(token Token, message string) *ResolveError {
+	where := "at end"
+	if token.Type != EOF {
+		where = fmt.Sprintf("at '%s'", token.Lexeme)
+	}
+	err := &ResolveError{Span: token.Span(), Where: where, Message: message}
+	r.errors = append(r.errors, err)
+	return err
+}
+
+func (r *resolver) visitBlockStmt(stmt *BlockStmt) interface{} {
+	r.beginScope()
+	r.resolveStmts(stmt.statements)
+	r.endScope()
+	return nil
+}
+
+func (r *resolver) visitBreakStmt(stmt *BreakStmt) interface{} {
+	return nil
+}
+
+func (r *resolver) visitClassStmt(stmt *ClassStmt) interface{} {
+	enclosingClass := r.currentClass
+	r.currentClass = classTypeClass
+	defer func() { r.currentClass = enclosingClass }()
+
+	r.declare(stmt.name)
+	r.define(stmt.name)
+
+	if stmt.superclass != nil {
+		if stmt.superclass.name.Lexeme == stmt.name.Lexeme {
+			r.error(stmt.superclass.name, "A class can't inherit from itself.")
+		}
+		r.currentClass = classTypeSubclass
+		r.resolveExpr(stmt.superclass)
+		// methods of a subclass are closures over a scope where "super" is bound to the superclass
+		r.beginScope()
+		r.scopes[len(r.scopes)-1]["super"] = true
+		defer r.endScope()
+	}
+
+	// methods are closures over a scope where "this" is bound to the instance
+	r.beginScope()
+	r.scopes[len(r.scopes)-1]["this"] = true
+	for _, method := range stmt.methods {
+		declaration := functionTypeMethod
+		if method.name.Lexeme == "init" {
+			declaration = functionTypeInitializer
+		}
+		r.resolveFunction(method, declaration)
+	}
+	r.endScope()
+	return nil
+}
+
+func (r *resolver) visitContinueStmt(stmt *ContinueStmt) interface{} {
+	return nil
+}
+
+func (r *resolver) visitExpressionStmt(stmt *ExpressionStmt) interface{} {
+	r.resolveExpr(stmt.expression)
+	return nil
+}
+
+func (r *resolver) visitForInStmt(stmt *ForInStmt) interface{} {
+	r.resolveExpr(stmt.iterable)
+	// each iteration defines the loop variable in its own scope, so that closures capture its current value
+	r.beginScope()
+	r.declare(stmt.name)
+	r.define(stmt.name)
+	r.resolveStmt(stmt.body)
+	r.endScope()
+	return nil
+}
+
+func (r *resolver) visitFunctionStmt(stmt *FunctionStmt) interface{} {
+	// define the name eagerly so that the function can refer to itself recursively
+	r.declare(stmt.name)
+	r.define(stmt.name)
+	r.resolveFunction(stmt, functionTypeFunction)
+	return nil
+}
+
+func (r *resolver) visitIfStmt(stmt *IfStmt) interface{} {
+	r.resolveExpr(stmt.condition)
+	r.resolveStmt(stmt.thenBranch)
+	if stmt.elseBranch != nil {
+		r.resolveStmt(stmt.elseBranch)
+	}
+	return nil
+}
+
+func (r *resolver) visitPrintStmt(stmt *PrintStmt) interface{} {
+	r.resolveExpr(stmt.expression)
+	return nil
+}
+
+func (r *resolver) visitReturnStmt(stmt *ReturnStmt) interface{} {
+	if r.currentFunction == functionTypeNone {
+		r.error(stmt.keyword, "Can't return from top-level code.")
+	}
+	if stmt.value != nil {
+		if r.currentFunction == functionTypeInitializer {
+			err := r.error(stmt.keyword, "Can't return a value from an initializer.")
+			err.Notes = []string{"initializers always return the new instance"}
+			err.Help = "use 'return;' to exit the initializer early"
+		}
+		r.resolveExpr(stmt.value)
+	}
+	return nil
+}
+
+func (r *resolver) visitThrowStmt(stmt *ThrowStmt) interface{} {
+	r.resolveExpr(stmt.value)
+	return nil
+}
+
+func (r *resolver) visitTryStmt(stmt *TryStmt) interface{} {
+	r.beginScope()
+	r.resolveStmts(stmt.body)
+	r.endScope()
+	if stmt.catchBody != nil {
+		// the error variable is declared in the scope of the catch body, like parameters
+		r.beginScope()
+		r.declare(stmt.catchName)
+		r.define(stmt.catchName)
+		r.resolveStmts(stmt.catchBody)
+		r.endScope()
+	}
+	if stmt.finallyBody != nil {
+		r.beginScope()
+		r.resolveStmts(stmt.finallyBody)
+		r.endScope()
+	}
+	return nil
+}
+
+func (r *resolver) visitVarStmt(stmt *VarStmt) interface{} {
+	r.declare(stmt.name)
+	if stmt.initializer != nil {
+		r.resolveExpr(stmt.initializer)
+	}
+	r.define(stmt.name)
+	return nil
+}
+
+func (r *resolver) visitWhileStmt(stmt *WhileStmt) interface{} {
+	r.resolveExpr(stmt.condition)
+	r.resolveStmt(stmt.body)
+	if stmt.increment != nil {
+		r.resolveExpr(stmt.increment)
+	}
+	return nil
+}
+
+func (r *resolver) visitAssignExpr(expr *AssignExpr) interface{} {
+	r.resolveExpr(expr.value)
+	r.resolveLocal(expr, expr.name)
+	return nil
+}
+
+func (r *resolver) visitBinaryExpr(expr *BinaryExpr) interface{} {
+	r.resolveExpr(expr.left)
+	r.resolveExpr(expr.right)
+	return nil
+}
+
+func (r *resolver) visitCallExpr(expr *CallExpr) interface{} {
+	r.resolveExpr(expr.callee)
+	for _, argument := range expr.arguments {
+		r.resolveExpr(argument)
+	}
+	return nil
+}
+
+func (r *resolver) visitGetExpr(expr *GetExpr) interface{} {
+	// properties are looked up dynamically so only the object is resolved
+	r.resolveExpr(expr.object)
+	return nil
+}
+
+func (r *resolver) visitGroupingExpr(expr *GroupingExpr) interface{} {
+	r.resolveExpr(expr.expression)
+	return nil
+}
+
+func (r *resolver) visitLiteralExpr(expr *LiteralExpr) interface{} {
+	return nil
+}
+
+func (r *resolver) visitLogicalExpr(expr *LogicalExpr) interface{} {
+	r.resolveExpr(expr.left)
+	r.resolveExpr(expr.right)
+	return nil
+}
+
+func (r *resolver) visitIndexExpr(expr *IndexExpr) interface{} {
+	r.resolveExpr(expr.object)
+	r.resolveExpr(expr.index)
+	return nil
+}
+
+func (r *resolver) visitIndexSetExpr(expr *IndexSetExpr) interface{} {
+	r.resolveExpr(expr.value)
+	r.resolveExpr(expr.object)
+	r.resolveExpr(expr.index)
+	return nil
+}
+
+func (r *resolver) visitListExpr(expr *ListExpr) interface{} {
+	for _, element := range expr.elements {
+		r.resolveExpr(element)
+	}
+	return nil
+}
+
+func (r *resolver) visitMapExpr(expr *MapExpr) interface{} {
+	for idx, key := range expr.keys {
+		r.resolveExpr(key)
+		r.resolveExpr(expr.values[idx])
+	}
+	return nil
+}
+
+func (r *resolver) visitSliceExpr(expr *SliceExpr) interface{} {
+	r.resolveExpr(expr.object)
+	if expr.start != nil {
+		r.resolveExpr(expr.start)
+	}
+	if expr.end != nil {
+		r.resolveExpr(expr.end)
+	}
+	return nil
+}
+
+func (r *resolver) visitSetExpr(expr *SetExpr) interface{} {
+	r.resolveExpr(expr.value)
+	r.resolveExpr(expr.object)
+	return nil
+}
+
+func (r *resolver) visitSuperExpr(expr *SuperExpr) interface{} {
+	if r.currentClass == classTypeNone {
+		r.error(expr.keyword, "Can't use 'super' outside of a class.")
+		return nil
+	}
+	if r.currentClass != classTypeSubclass {
+		r.error(expr.keyword, "Can't use 'super' in a class with no superclass.").Help =
+			"declare a superclass with 'class Name < Superclass'"
+		return nil
+	}
+	r.resolveLocal(expr, expr.keyword)
+	return nil
+}
+
+func (r *resolver) visitThisExpr(expr *ThisExpr) interface{} {
+	if r.currentClass == classTypeNone {
+		r.error(expr.keyword, "Can't use 'this' outside of a class.")
+		return nil
+	}
+	r.resolveLocal(expr, expr.keyword)
+	return nil
+}
+
+func (r *resolver) visitUnaryExpr(expr *UnaryExpr) interface{} {
+	r.resolveExpr(expr.right)
+	return nil
+}
+
+func (r *resolver) visitVariableExpr(expr *VariableExpr) interface{} {
+	if len(r.scopes) > 0 {
+		if defined, ok := r.scopes[len(r.scopes)-1][expr.name.Lexeme]; ok && !defined {
+			r.error(expr.name, "Can't read local variable in its own initializer.").Help =
+				"give the new variable another name to read the shadowed one"
+		}
+	}
+	r.resolveLocal(expr, expr.name)
+	return nil
+}
+
+// ResolveError is a static error found when resolving variables, such as a misplaced return statement.
+type ResolveError struct {
+	// Span locates the token where the error was found. Its fields, such as Line, are promoted.
+	Span
+	// Where describes the location for humans, e.g. "at 'return'".
+	Where   string
+	Message string
+	// Notes give additional context and Help suggests a fix, if any.
+	Notes []string
+	Help  string
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("[%s] Error %s: %s", e.Span, e.Where, e.Message)
+}
+
+func (e *ResolveError) diagnostic() diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Severity: diagnostics.Error,
+		Code:     "resolve-error",
+		Message:  e.Message,
+		Span:     e.Span,
+		Notes:    e.Notes,
+		Help:     e.Help,
+	}
+}
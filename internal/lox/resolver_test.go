@@ -0,0 +1,79 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolverErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected []string
+	}{
+		{
+			name:     "no errors",
+			source:   "var a = 1; { var b = a; var a = b; } fun f(a) { var b = a; return b; }",
+			expected: []string{},
+		},
+		{
+			name:     "read local in its own initializer",
+			source:   "var a = 1;\n{\n  var a = a;\n}",
+			expected: []string{"[line 3:11] Error at 'a': Can't read local variable in its own initializer."},
+		},
+		{
+			name:     "redeclare local",
+			source:   "{\n  var a = 1;\n  var a = 2;\n}",
+			expected: []string{"[line 3:7] Error at 'a': Already a variable with this name in this scope."},
+		},
+		{
+			name:     "redeclare parameter",
+			source:   "fun f(a, a) {}",
+			expected: []string{"[line 1:10] Error at 'a': Already a variable with this name in this scope."},
+		},
+		{
+			name:     "top-level return",
+			source:   "return 1;",
+			expected: []string{"[line 1:1] Error at 'return': Can't return from top-level code."},
+		},
+		{
+			name:     "this outside of a class",
+			source:   "fun f() {\n  print this;\n}",
+			expected: []string{"[line 2:9] Error at 'this': Can't use 'this' outside of a class."},
+		},
+		{
+			name:     "return a value from an initializer",
+			source:   "class A {\n  init() {\n    return 1;\n  }\n}",
+			expected: []string{"[line 3:5] Error at 'return': Can't return a value from an initializer."},
+		},
+		{
+			name:     "inherit from itself",
+			source:   "class Oops < Oops {}",
+			expected: []string{"[line 1:14] Error at 'Oops': A class can't inherit from itself."},
+		},
+		{
+			name:     "super outside of a class",
+			source:   "super.method();",
+			expected: []string{"[line 1:1] Error at 'super': Can't use 'super' outside of a class."},
+		},
+		{
+			name:     "super without a superclass",
+			source:   "class A {\n  f() {\n    super.f();\n  }\n}",
+			expected: []string{"[line 3:5] Error at 'super': Can't use 'super' in a class with no superclass."},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statements := NewParser(NewScanner(tc.source).ScanTokens()).Parse()
+			resolver := NewResolver(NewInterpreter())
+			resolver.Resolve(statements)
+			actual := make([]string, 0)
+			for _, err := range resolver.errors {
+				actual = append(actual, err.Error())
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
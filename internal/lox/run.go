@@ -0,0 +1,204 @@
+package lox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nockty/glox/internal/diagnostics"
+)
+
+// ErrorList is a list of static errors found in source code, which are reported together.
+type ErrorList []error
+
+func (l ErrorList) Error() string {
+	messages := make([]string, 0, len(l))
+	for _, err := range l {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Is reports whether one of the errors of the list matches target, so that errors.Is looks into them.
+func (l ErrorList) Is(target error) bool {
+	for _, err := range l {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the list that matches target, so that errors.As looks into them.
+func (l ErrorList) As(target interface{}) bool {
+	for _, err := range l {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Diagnostics returns the diagnostics of the errors of the list, to render them.
+func (l ErrorList) Diagnostics() []diagnostics.Diagnostic {
+	result := make([]diagnostics.Diagnostic, 0, len(l))
+	for _, err := range l {
+		if err, ok := err.(interface{ diagnostic() diagnostics.Diagnostic }); ok {
+			result = append(result, err.diagnostic())
+			continue
+		}
+		result = append(result, diagnostics.Diagnostic{Severity: diagnostics.Error, Message: err.Error()})
+	}
+	return result
+}
+
+// Report writes an error about source read from the named file on w. Static errors are rendered with an
+// excerpt of the source, other errors as text.
+func Report(w io.Writer, err error, file, source string) {
+	var list ErrorList
+	if errors.As(err, &list) {
+		renderer := diagnostics.NewRenderer(w)
+		renderer.AddSource(file, source)
+		renderer.RenderAll(list.Diagnostics())
+		return
+	}
+	fmt.Fprintln(w, err)
+}
+
+// Parse scans and parses source read from the named file, whose name is empty when the source doesn't come
+// from a file. Scan or parse errors are returned in an ErrorList.
+func Parse(file, source string) ([]Stmt, error) {
+	scanner := NewFileScanner(file, source)
+	tokens := scanner.ScanTokens()
+	if scanner.HadErrors() {
+		return nil, ErrorList(scanner.Errors())
+	}
+	parser := NewParser(tokens)
+	statements := parser.Parse()
+	if parser.HadErrors() {
+		return nil, ErrorList(parser.Errors())
+	}
+	return statements, nil
+}
+
+// ParseExpression scans and parses source made of a single expression, read from the named file. Scan or
+// parse errors are returned in an ErrorList.
+func ParseExpression(file, source string) (Expr, error) {
+	scanner := NewFileScanner(file, source)
+	tokens := scanner.ScanTokens()
+	if scanner.HadErrors() {
+		return nil, ErrorList(scanner.Errors())
+	}
+	parser := NewParser(tokens)
+	expr := parser.ParseExpression()
+	if parser.HadErrors() {
+		return nil, ErrorList(parser.Errors())
+	}
+	return expr, nil
+}
+
+// Compile parses, resolves and compiles source read from the named file to bytecode. Static errors are
+// returned in an ErrorList.
+func Compile(file, source string) (*Function, error) {
+	statements, err := Parse(file, source)
+	if err != nil {
+		return nil, err
+	}
+	resolver := NewResolver(NewInterpreter())
+	resolver.Resolve(statements)
+	if resolver.HadErrors() {
+		return nil, ErrorList(resolver.Errors())
+	}
+	compiler := NewCompiler()
+	function := compiler.Compile(statements)
+	if compiler.HadErrors() {
+		return nil, ErrorList(compiler.Errors())
+	}
+	return function, nil
+}
+
+// Check parses and resolves source read from the named file without running it, and returns its diagnostics.
+// Static errors prevent the source from running at all. When there are none, the diagnostics are notes about
+// what the virtual machine doesn't support, which only prevent the source from running on it.
+func Check(file, source string) []diagnostics.Diagnostic {
+	statements, err := Parse(file, source)
+	var list ErrorList
+	if errors.As(err, &list) {
+		return list.Diagnostics()
+	}
+	resolver := NewResolver(NewInterpreter())
+	resolver.Resolve(statements)
+	if resolver.HadErrors() {
+		return ErrorList(resolver.Errors()).Diagnostics()
+	}
+	compiler := NewCompiler()
+	compiler.Compile(statements)
+	notes := ErrorList(compiler.Errors()).Diagnostics()
+	for idx := range notes {
+		notes[idx].Severity = diagnostics.Note
+	}
+	return notes
+}
+
+// Run parses, resolves and interprets source read from the named file with a fresh interpreter. Static
+// errors are returned in an ErrorList, and prevent the source from running.
+func Run(file, source string) error {
+	return NewInterpreter().Run(file, source)
+}
+
+// Run parses, resolves and interprets source read from the named file. The global state is kept from
+// previous runs. Errors are reported on the error output of the interpreter, if any, and returned.
+func (i *interpreter) Run(file, source string) error {
+	return i.RunContext(context.Background(), file, source)
+}
+
+// RunContext is like Run, but stops with a runtime error wrapping the error of ctx when ctx is done.
+func (i *interpreter) RunContext(ctx context.Context, file, source string) error {
+	statements, err := Parse(file, source)
+	if err != nil {
+		return i.report(err, file, source)
+	}
+	resolver := NewResolver(i)
+	resolver.Resolve(statements)
+	if resolver.HadErrors() {
+		return i.report(ErrorList(resolver.Errors()), file, source)
+	}
+	// Interpret reports runtime errors itself
+	return i.InterpretContext(ctx, statements)
+}
+
+// Eval parses, resolves and evaluates source made of a single expression, read from the named file. The
+// expression can use the globals defined by previous runs. Errors are reported on the error output of the
+// interpreter, if any, and returned.
+func (i *interpreter) Eval(file, source string) (interface{}, error) {
+	return i.EvalContext(context.Background(), file, source)
+}
+
+// EvalContext is like Eval, but stops with a runtime error wrapping the error of ctx when ctx is done.
+func (i *interpreter) EvalContext(ctx context.Context, file, source string) (interface{}, error) {
+	expr, err := ParseExpression(file, source)
+	if err != nil {
+		return nil, i.report(err, file, source)
+	}
+	resolver := NewResolver(i)
+	resolver.resolveExpr(expr)
+	if resolver.HadErrors() {
+		return nil, i.report(ErrorList(resolver.Errors()), file, source)
+	}
+	defer i.start(ctx)()
+	value := i.evaluate(expr)
+	if err, ok := value.(*RuntimeError); ok {
+		return nil, i.report(err, file, source)
+	}
+	return value, nil
+}
+
+// report writes err on the error output of the interpreter, if any, and returns it.
+func (i *interpreter) report(err error, file, source string) error {
+	if i.errorOutput != nil {
+		Report(i.errorOutput, err, file, source)
+	}
+	return err
+}
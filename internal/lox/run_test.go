@@ -0,0 +1,181 @@
+package lox
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/nockty/glox/internal/diagnostics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStaticErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected []string
+	}{
+		{
+			name:     "scan errors",
+			source:   "var a = @;\nprint #;",
+			expected: []string{"[test.lox:1:9] Scan Error at '@': Unidentified symbol.", "[test.lox:2:7] Scan Error at '#': Unidentified symbol."},
+		},
+		{
+			name:     "parse errors",
+			source:   "print 1 +;\nvar = 2;",
+			expected: []string{"[test.lox:1:10] Error at ';': Expect expression.", "[test.lox:2:1] Error at 'var': Expect variable name."},
+		},
+		{
+			name:     "break and continue outside of loops",
+			source:   "break;\nwhile (true) { fun f() { continue; } }",
+			expected: []string{"[test.lox:1:1] Error at 'break': Can't use 'break' outside of a loop.", "[test.lox:2:26] Error at 'continue': Can't use 'continue' outside of a loop."},
+		},
+		{
+			name:     "collection literals",
+			source:   "print [1, 2;\nprint {\"a\" 1};\nprint {1: 2;",
+			expected: []string{"[test.lox:1:11] Error at '2': Expect ']' after list elements.", "[test.lox:2:8] Error at '\"a\"': Expect ':' after map key.", "[test.lox:3:11] Error at '2': Expect '}' after map entries."},
+		},
+		{
+			name:     "for-in loops",
+			source:   "for (var x in [1] print x;\nfor (var x in) {}",
+			expected: []string{"[test.lox:1:17] Error at ']': Expect ')' after for-in clause.", "[test.lox:2:14] Error at ')': Expect expression."},
+		},
+		{
+			name:     "exceptions",
+			source:   "throw;\ntry {}\ntry {} catch {}",
+			expected: []string{"[test.lox:1:6] Error at ';': Expect expression.", "[test.lox:2:6] Error at '}': Expect 'catch' or 'finally' after try block.", "[test.lox:3:8] Error at 'catch': Expect '(' after 'catch'."},
+		},
+		{
+			name:     "resolve errors",
+			source:   "return 1;",
+			expected: []string{"[test.lox:1:1] Error at 'return': Can't return from top-level code."},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := NewInterpreter(WithOutput(&output)).Run("test.lox", tc.source)
+			assert.Empty(t, output.String())
+			var list ErrorList
+			require.True(t, errors.As(err, &list))
+			messages := make([]string, 0, len(list))
+			for _, err := range list {
+				messages = append(messages, err.Error())
+			}
+			assert.Equal(t, tc.expected, messages)
+			assert.Len(t, list.Diagnostics(), len(tc.expected))
+		})
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	_, err := Parse("test.lox", "var a = 1;\na = ;")
+	var list ErrorList
+	require.True(t, errors.As(err, &list))
+	require.Len(t, list, 1)
+	parseErr, ok := list[0].(*ParseError)
+	require.True(t, ok)
+	assert.Equal(t, 2, parseErr.Line)
+	assert.Equal(t, 5, parseErr.Column)
+	assert.Equal(t, "test.lox", parseErr.File)
+	assert.Equal(t, "at ';'", parseErr.Where)
+	assert.Equal(t, "Expect expression.", parseErr.Message)
+
+	// errors.As and errors.Is look into the errors of the list
+	var found *ParseError
+	require.True(t, errors.As(err, &found))
+	assert.Same(t, parseErr, found)
+	assert.True(t, errors.Is(err, parseErr))
+	assert.False(t, errors.Is(err, errors.New("other")))
+
+	scanner := NewScanner(`"open`)
+	scanner.ScanTokens()
+	require.True(t, scanner.HadErrors())
+	require.Len(t, scanner.Errors(), 1)
+	scanErr := scanner.Errors()[0].(*ScanError)
+	assert.Equal(t, 1, scanErr.Line)
+	assert.Equal(t, "Unterminated string.", scanErr.Message)
+	assert.NotEmpty(t, scanErr.Help)
+
+	statements, err := Parse("", "class A { init() { return 1; } }")
+	require.NoError(t, err)
+	resolver := NewResolver(NewInterpreter())
+	resolver.Resolve(statements)
+	require.Len(t, resolver.Errors(), 1)
+	resolveErr := resolver.Errors()[0].(*ResolveError)
+	assert.Equal(t, "at 'return'", resolveErr.Where)
+	assert.Equal(t, "Can't return a value from an initializer.", resolveErr.Message)
+	assert.NotEmpty(t, resolveErr.Notes)
+}
+
+func TestRunKeepsGlobals(t *testing.T) {
+	var output bytes.Buffer
+	interpreter := NewInterpreter(WithOutput(&output))
+	require.NoError(t, interpreter.Run("", "var a = 1; fun f() { return a + 1; }"))
+	err := interpreter.Run("", "a = f(); print a; print a + nil;")
+	require.NoError(t, interpreter.Run("", "print f();"))
+	assert.Equal(t, "2\n3\n", output.String())
+	require.Error(t, err)
+	var list ErrorList
+	assert.False(t, errors.As(err, &list), "runtime errors are not static errors")
+	assert.Equal(t, "+: Operands must be two numbers or two strings.\n[line 1:27]", err.Error())
+}
+
+func TestCompile(t *testing.T) {
+	function, err := Compile("", "print 1 + 2;")
+	require.NoError(t, err)
+	var output bytes.Buffer
+	require.NoError(t, NewVM(WithVMOutput(&output)).Interpret(function))
+	assert.Equal(t, "3\n", output.String())
+
+	_, err = Compile("", "print this;")
+	var list ErrorList
+	require.True(t, errors.As(err, &list))
+	assert.IsType(t, &ResolveError{}, list[0])
+}
+
+func TestCompileUnsupported(t *testing.T) {
+	_, err := Compile("", "var xs = [1, 2];\nprint xs[0];\nprint {};\nfor (var x in xs) {}\ntry {} finally {}")
+	var list ErrorList
+	require.True(t, errors.As(err, &list))
+	require.Len(t, list, 5)
+	assert.IsType(t, &CompileError{}, list[0])
+	assert.Equal(t, "[line 1:10] Compile Error: Lists are not supported by the virtual machine.", list[0].Error())
+	assert.Equal(t, "[line 2:11] Compile Error: Lists and maps are not supported by the virtual machine.", list[1].Error())
+	assert.Equal(t, "[line 3:7] Compile Error: Maps are not supported by the virtual machine.", list[2].Error())
+	assert.Equal(t, "[line 4:12] Compile Error: For-in loops are not supported by the virtual machine.", list[3].Error())
+	assert.Equal(t, "[line 5:1] Compile Error: Exceptions are not supported by the virtual machine.", list[4].Error())
+}
+
+func TestCheck(t *testing.T) {
+	// scripts using features the virtual machine doesn't support only get notes
+	testCases := []struct {
+		name    string
+		source  string
+		message string
+	}{
+		{"lists", "var xs = [1, 2, 3];", "Lists are not supported by the virtual machine."},
+		{"maps", `var m = {"a": 1};`, "Maps are not supported by the virtual machine."},
+		{"for-in loops", "for (var c in \"abc\") print c;", "For-in loops are not supported by the virtual machine."},
+		{"exceptions", "try { throw 1; } catch (e) {}", "Exceptions are not supported by the virtual machine."},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			found := Check("test.lox", tc.source)
+			require.Len(t, found, 1)
+			assert.Equal(t, diagnostics.Note, found[0].Severity)
+			assert.Equal(t, tc.message, found[0].Message)
+		})
+	}
+
+	assert.Empty(t, Check("test.lox", "print 1;"))
+	found := Check("test.lox", "var xs = [1];\nprint xs +;")
+	require.Len(t, found, 1)
+	assert.Equal(t, diagnostics.Error, found[0].Severity)
+	assert.Equal(t, "Expect expression.", found[0].Message)
+	found = Check("test.lox", "var xs = [1];\nreturn xs;")
+	require.Len(t, found, 1)
+	assert.Equal(t, diagnostics.Error, found[0].Severity)
+	assert.Equal(t, "Can't return from top-level code.", found[0].Message)
+}
@@ -3,37 +3,53 @@ package lox
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/nockty/glox/internal/diagnostics"
 )
 
 var keywords = map[string]TokenType{
-	"and":    And,
-	"class":  Class,
-	"else":   Else,
-	"false":  False,
-	"fun":    Fun,
-	"for":    For,
-	"if":     If,
-	"nil":    Nil,
-	"or":     Or,
-	"print":  Print,
-	"return": Return,
-	"super":  Super,
-	"this":   This,
-	"true":   True,
-	"var":    Var,
-	"while":  While,
+	"and":      And,
+	"break":    Break,
+	"catch":    Catch,
+	"class":    Class,
+	"continue": Continue,
+	"else":     Else,
+	"false":    False,
+	"finally":  Finally,
+	"fun":      Fun,
+	"for":      For,
+	"if":       If,
+	"in":       In,
+	"nil":      Nil,
+	"or":       Or,
+	"print":    Print,
+	"return":   Return,
+	"super":    Super,
+	"this":     This,
+	"throw":    Throw,
+	"true":     True,
+	"try":      Try,
+	"var":      Var,
+	"while":    While,
 }
 
 type Scanner struct {
+	// file is the name of the source file, if any
+	file   string
 	source string
 	tokens []Token
-	errors []*scanError
+	errors []*ScanError
 	// first character in the lexeme being scanned
 	start int
 	// character currently being considered
 	current int
 	// source line where the current character is
 	line int
+	// offset of the first character of the current line
+	lineStart int
+	// line and column where the lexeme being scanned starts
+	startLine   int
+	startColumn int
 }
 
 func NewScanner(source string) *Scanner {
@@ -43,23 +59,41 @@ func NewScanner(source string) *Scanner {
 	}
 }
 
+// NewFileScanner creates a scanner for source read from the named file. The name appears in the spans of
+// tokens and in errors.
+func NewFileScanner(file, source string) *Scanner {
+	scanner := NewScanner(source)
+	scanner.file = file
+	return scanner
+}
+
 func (s *Scanner) ScanTokens() []Token {
 	for !s.isAtEnd() {
 		// Beginning of the next lexeme
 		s.start = s.current
+		s.startLine = s.line
+		s.startColumn = s.current - s.lineStart + 1
 		s.scanToken()
 	}
-	s.tokens = append(s.tokens, NewToken(EOF, "", nil, s.line))
+	s.start = s.current
+	s.startLine = s.line
+	s.startColumn = s.current - s.lineStart + 1
+	s.addToken(EOF, nil)
 	return s.tokens
 }
 
+// HadErrors reports whether there were errors while scanning.
 func (s *Scanner) HadErrors() bool {
-	hadErrors := false
+	return len(s.errors) > 0
+}
+
+// Errors returns the errors found while scanning, as *ScanError.
+func (s *Scanner) Errors() []error {
+	result := make([]error, 0, len(s.errors))
 	for _, err := range s.errors {
-		println(err.Error())
-		hadErrors = true
+		result = append(result, err)
 	}
-	return hadErrors
+	return result
 }
 
 func (s *Scanner) scanToken() {
@@ -73,6 +107,12 @@ func (s *Scanner) scanToken() {
 		s.addToken(LeftBrace, nil)
 	case '}':
 		s.addToken(RightBrace, nil)
+	case '[':
+		s.addToken(LeftBracket, nil)
+	case ']':
+		s.addToken(RightBracket, nil)
+	case ':':
+		s.addToken(Colon, nil)
 	case ',':
 		s.addToken(Comma, nil)
 	case '.':
@@ -122,7 +162,7 @@ func (s *Scanner) scanToken() {
 		// ignore whitespace
 		break
 	case '\n':
-		s.line++
+		s.newLine()
 	case '"':
 		s.string()
 	default:
@@ -141,6 +181,16 @@ func (s *Scanner) advance() byte {
 	return s.source[s.current-1]
 }
 
+func (s *Scanner) previous() byte {
+	return s.source[s.current-1]
+}
+
+// newLine records that the previous character ends a line.
+func (s *Scanner) newLine() {
+	s.line++
+	s.lineStart = s.current
+}
+
 func (s *Scanner) match(expected byte) bool {
 	if s.isAtEnd() {
 		return false
@@ -168,13 +218,13 @@ func (s *Scanner) peekNext() byte {
 
 func (s *Scanner) string() {
 	for s.peek() != '"' && !s.isAtEnd() {
-		if s.peek() == '\n' {
-			s.line++
-		}
 		s.advance()
+		if s.previous() == '\n' {
+			s.newLine()
+		}
 	}
 	if s.isAtEnd() {
-		s.addError("Unterminated string.")
+		s.addError("Unterminated string.").Help = `add a closing '"'`
 		return
 	}
 	// closing "
@@ -218,15 +268,33 @@ func (s *Scanner) identifier() {
 
 func (s *Scanner) addToken(tokenType TokenType, literal interface{}) {
 	text := s.source[s.start:s.current]
-	s.tokens = append(s.tokens, NewToken(tokenType, text, literal, s.line))
+	token := NewToken(tokenType, text, literal, s.startLine)
+	token.Column = s.startColumn
+	token.Offset = s.start
+	token.Length = s.current - s.start
+	token.File = s.file
+	s.tokens = append(s.tokens, token)
 }
 
-func (s *Scanner) addError(message string) {
+// span returns the span of the lexeme being scanned.
+func (s *Scanner) span() Span {
+	return Span{
+		File:   s.file,
+		Line:   s.startLine,
+		Column: s.startColumn,
+		Offset: s.start,
+		Length: s.current - s.start,
+	}
+}
+
+func (s *Scanner) addError(message string) *ScanError {
 	text := s.source[s.start:s.current]
 	text = strconv.QuoteToASCII(text)
 	text = text[1 : len(text)-1]
 	where := fmt.Sprintf("at '%s'", text)
-	s.errors = append(s.errors, &scanError{line: s.line, where: where, message: message})
+	err := &ScanError{Span: s.span(), Where: where, Message: message}
+	s.errors = append(s.errors, err)
+	return err
 }
 
 func (s *Scanner) isAtEnd() bool {
@@ -247,12 +315,27 @@ func isAlphaNumeric(c byte) bool {
 	return isDigit(c) || isAlpha(c)
 }
 
-type scanError struct {
-	line    int
-	where   string
-	message string
+// ScanError is an error in the lexical grammar, such as an unexpected character.
+type ScanError struct {
+	// Span locates the invalid lexeme. Its fields, such as Line, are promoted.
+	Span
+	// Where describes the location for humans, e.g. "at '@'".
+	Where   string
+	Message string
+	// Help suggests a fix, if any.
+	Help string
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("[%s] Scan Error %s: %s", e.Span, e.Where, e.Message)
 }
 
-func (e *scanError) Error() string {
-	return fmt.Sprintf("[line %d] Scan Error %s: %s", e.line, e.where, e.message)
+func (e *ScanError) diagnostic() diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Severity: diagnostics.Error,
+		Code:     "scan-error",
+		Message:  e.Message,
+		Span:     e.Span,
+		Help:     e.Help,
+	}
 }
@@ -0,0 +1,6 @@
+package lox
+
+import "github.com/nockty/glox/internal/diagnostics"
+
+// Span locates a range of source code.
+type Span = diagnostics.Span
@@ -0,0 +1,81 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSpans(t *testing.T) {
+	source := "var s = \"two\nlines\";\n  print s;"
+	tokens := NewFileScanner("test.lox", source).ScanTokens()
+	expected := []struct {
+		lexeme string
+		span   Span
+	}{
+		{"var", Span{File: "test.lox", Line: 1, Column: 1, Offset: 0, Length: 3}},
+		{"s", Span{File: "test.lox", Line: 1, Column: 5, Offset: 4, Length: 1}},
+		{"=", Span{File: "test.lox", Line: 1, Column: 7, Offset: 6, Length: 1}},
+		// a multi-line string is located at its start
+		{"\"two\nlines\"", Span{File: "test.lox", Line: 1, Column: 9, Offset: 8, Length: 11}},
+		{";", Span{File: "test.lox", Line: 2, Column: 7, Offset: 19, Length: 1}},
+		{"print", Span{File: "test.lox", Line: 3, Column: 3, Offset: 23, Length: 5}},
+		{"s", Span{File: "test.lox", Line: 3, Column: 9, Offset: 29, Length: 1}},
+		{";", Span{File: "test.lox", Line: 3, Column: 10, Offset: 30, Length: 1}},
+		{"", Span{File: "test.lox", Line: 3, Column: 11, Offset: 31, Length: 0}},
+	}
+	require.Len(t, tokens, len(expected))
+	for i, token := range tokens {
+		assert.Equal(t, expected[i].lexeme, token.Lexeme)
+		assert.Equal(t, expected[i].span, token.Span(), "token %q", token.Lexeme)
+	}
+}
+
+func TestNodeSpans(t *testing.T) {
+	source := "var a = 1;\nprint f(a + 2, (3)).x;\nif (a) { a = -a; }"
+	statements := NewParser(NewScanner(source).ScanTokens()).Parse()
+	require.Len(t, statements, 3)
+
+	// text returns the source code covered by a node
+	text := func(span Span) string {
+		return source[span.Offset:span.End()]
+	}
+	assert.Equal(t, "var a = 1;", text(statements[0].Span()))
+	print := statements[1].(*PrintStmt)
+	assert.Equal(t, "print f(a + 2, (3)).x;", text(print.Span()))
+	get := print.expression.(*GetExpr)
+	assert.Equal(t, "f(a + 2, (3)).x", text(get.Span()))
+	call := get.object.(*CallExpr)
+	assert.Equal(t, "f(a + 2, (3))", text(call.Span()))
+	assert.Equal(t, "a + 2", text(call.arguments[0].Span()))
+	assert.Equal(t, "(3)", text(call.arguments[1].Span()))
+	assert.Equal(t, Span{Line: 2, Column: 7, Offset: 17, Length: 15}, get.Span())
+
+	ifStmt := statements[2].(*IfStmt)
+	assert.Equal(t, "if (a) { a = -a; }", text(ifStmt.Span()))
+	block := ifStmt.thenBranch.(*BlockStmt)
+	assert.Equal(t, "{ a = -a; }", text(block.Span()))
+	assign := block.statements[0].(*ExpressionStmt).expression
+	assert.Equal(t, "a = -a", text(assign.Span()))
+	assert.Equal(t, "-a", text(assign.(*AssignExpr).value.Span()))
+}
+
+func TestErrorPositions(t *testing.T) {
+	scanner := NewFileScanner("test.lox", "var a = 1;\n  @")
+	scanner.ScanTokens()
+	require.Len(t, scanner.errors, 1)
+	assert.Equal(t, "[test.lox:2:3] Scan Error at '@': Unidentified symbol.", scanner.errors[0].Error())
+
+	parser := NewParser(NewFileScanner("test.lox", "print 1 +;").ScanTokens())
+	parser.Parse()
+	require.Len(t, parser.errors, 1)
+	assert.Equal(t, "[test.lox:1:10] Error at ';': Expect expression.", parser.errors[0].Error())
+
+	statements := NewParser(NewFileScanner("test.lox", "var a = 1;\nprint a + nil;").ScanTokens()).Parse()
+	interpreter := NewInterpreter()
+	NewResolver(interpreter).Resolve(statements)
+	err := interpreter.Interpret(statements)
+	require.Error(t, err)
+	assert.Equal(t, "+: Operands must be two numbers or two strings.\n[test.lox:2:9]", err.Error())
+}
@@ -2,6 +2,9 @@
 package lox
 
 type Stmt interface {
+	// Span returns the location of the node in the source.
+	Span() Span
+	setSpan(span Span)
 	Accept(v visitorStmt) interface{}
 	AcceptBool(v visitorStmtBool) bool
 	AcceptString(v visitorStmtString) string
@@ -26,186 +29,347 @@ type Stmt interface {
 
 type visitorStmt interface {
 	visitBlockStmt(*BlockStmt) interface{}
+	visitBreakStmt(*BreakStmt) interface{}
+	visitClassStmt(*ClassStmt) interface{}
+	visitContinueStmt(*ContinueStmt) interface{}
 	visitExpressionStmt(*ExpressionStmt) interface{}
+	visitForInStmt(*ForInStmt) interface{}
+	visitFunctionStmt(*FunctionStmt) interface{}
 	visitIfStmt(*IfStmt) interface{}
 	visitPrintStmt(*PrintStmt) interface{}
+	visitReturnStmt(*ReturnStmt) interface{}
+	visitThrowStmt(*ThrowStmt) interface{}
+	visitTryStmt(*TryStmt) interface{}
 	visitVarStmt(*VarStmt) interface{}
 	visitWhileStmt(*WhileStmt) interface{}
 }
 
 type visitorStmtBool interface {
 	visitBlockStmt(*BlockStmt) bool
+	visitBreakStmt(*BreakStmt) bool
+	visitClassStmt(*ClassStmt) bool
+	visitContinueStmt(*ContinueStmt) bool
 	visitExpressionStmt(*ExpressionStmt) bool
+	visitForInStmt(*ForInStmt) bool
+	visitFunctionStmt(*FunctionStmt) bool
 	visitIfStmt(*IfStmt) bool
 	visitPrintStmt(*PrintStmt) bool
+	visitReturnStmt(*ReturnStmt) bool
+	visitThrowStmt(*ThrowStmt) bool
+	visitTryStmt(*TryStmt) bool
 	visitVarStmt(*VarStmt) bool
 	visitWhileStmt(*WhileStmt) bool
 }
 
 type visitorStmtString interface {
 	visitBlockStmt(*BlockStmt) string
+	visitBreakStmt(*BreakStmt) string
+	visitClassStmt(*ClassStmt) string
+	visitContinueStmt(*ContinueStmt) string
 	visitExpressionStmt(*ExpressionStmt) string
+	visitForInStmt(*ForInStmt) string
+	visitFunctionStmt(*FunctionStmt) string
 	visitIfStmt(*IfStmt) string
 	visitPrintStmt(*PrintStmt) string
+	visitReturnStmt(*ReturnStmt) string
+	visitThrowStmt(*ThrowStmt) string
+	visitTryStmt(*TryStmt) string
 	visitVarStmt(*VarStmt) string
 	visitWhileStmt(*WhileStmt) string
 }
 
 type visitorStmtInt interface {
 	visitBlockStmt(*BlockStmt) int
+	visitBreakStmt(*BreakStmt) int
+	visitClassStmt(*ClassStmt) int
+	visitContinueStmt(*ContinueStmt) int
 	visitExpressionStmt(*ExpressionStmt) int
+	visitForInStmt(*ForInStmt) int
+	visitFunctionStmt(*FunctionStmt) int
 	visitIfStmt(*IfStmt) int
 	visitPrintStmt(*PrintStmt) int
+	visitReturnStmt(*ReturnStmt) int
+	visitThrowStmt(*ThrowStmt) int
+	visitTryStmt(*TryStmt) int
 	visitVarStmt(*VarStmt) int
 	visitWhileStmt(*WhileStmt) int
 }
 
 type visitorStmtInt8 interface {
 	visitBlockStmt(*BlockStmt) int8
+	visitBreakStmt(*BreakStmt) int8
+	visitClassStmt(*ClassStmt) int8
+	visitContinueStmt(*ContinueStmt) int8
 	visitExpressionStmt(*ExpressionStmt) int8
+	visitForInStmt(*ForInStmt) int8
+	visitFunctionStmt(*FunctionStmt) int8
 	visitIfStmt(*IfStmt) int8
 	visitPrintStmt(*PrintStmt) int8
+	visitReturnStmt(*ReturnStmt) int8
+	visitThrowStmt(*ThrowStmt) int8
+	visitTryStmt(*TryStmt) int8
 	visitVarStmt(*VarStmt) int8
 	visitWhileStmt(*WhileStmt) int8
 }
 
 type visitorStmtInt16 interface {
 	visitBlockStmt(*BlockStmt) int16
+	visitBreakStmt(*BreakStmt) int16
+	visitClassStmt(*ClassStmt) int16
+	visitContinueStmt(*ContinueStmt) int16
 	visitExpressionStmt(*ExpressionStmt) int16
+	visitForInStmt(*ForInStmt) int16
+	visitFunctionStmt(*FunctionStmt) int16
 	visitIfStmt(*IfStmt) int16
 	visitPrintStmt(*PrintStmt) int16
+	visitReturnStmt(*ReturnStmt) int16
+	visitThrowStmt(*ThrowStmt) int16
+	visitTryStmt(*TryStmt) int16
 	visitVarStmt(*VarStmt) int16
 	visitWhileStmt(*WhileStmt) int16
 }
 
 type visitorStmtInt32 interface {
 	visitBlockStmt(*BlockStmt) int32
+	visitBreakStmt(*BreakStmt) int32
+	visitClassStmt(*ClassStmt) int32
+	visitContinueStmt(*ContinueStmt) int32
 	visitExpressionStmt(*ExpressionStmt) int32
+	visitForInStmt(*ForInStmt) int32
+	visitFunctionStmt(*FunctionStmt) int32
 	visitIfStmt(*IfStmt) int32
 	visitPrintStmt(*PrintStmt) int32
+	visitReturnStmt(*ReturnStmt) int32
+	visitThrowStmt(*ThrowStmt) int32
+	visitTryStmt(*TryStmt) int32
 	visitVarStmt(*VarStmt) int32
 	visitWhileStmt(*WhileStmt) int32
 }
 
 type visitorStmtInt64 interface {
 	visitBlockStmt(*BlockStmt) int64
+	visitBreakStmt(*BreakStmt) int64
+	visitClassStmt(*ClassStmt) int64
+	visitContinueStmt(*ContinueStmt) int64
 	visitExpressionStmt(*ExpressionStmt) int64
+	visitForInStmt(*ForInStmt) int64
+	visitFunctionStmt(*FunctionStmt) int64
 	visitIfStmt(*IfStmt) int64
 	visitPrintStmt(*PrintStmt) int64
+	visitReturnStmt(*ReturnStmt) int64
+	visitThrowStmt(*ThrowStmt) int64
+	visitTryStmt(*TryStmt) int64
 	visitVarStmt(*VarStmt) int64
 	visitWhileStmt(*WhileStmt) int64
 }
 
 type visitorStmtUint interface {
 	visitBlockStmt(*BlockStmt) uint
+	visitBreakStmt(*BreakStmt) uint
+	visitClassStmt(*ClassStmt) uint
+	visitContinueStmt(*ContinueStmt) uint
 	visitExpressionStmt(*ExpressionStmt) uint
+	visitForInStmt(*ForInStmt) uint
+	visitFunctionStmt(*FunctionStmt) uint
 	visitIfStmt(*IfStmt) uint
 	visitPrintStmt(*PrintStmt) uint
+	visitReturnStmt(*ReturnStmt) uint
+	visitThrowStmt(*ThrowStmt) uint
+	visitTryStmt(*TryStmt) uint
 	visitVarStmt(*VarStmt) uint
 	visitWhileStmt(*WhileStmt) uint
 }
 
 type visitorStmtUint8 interface {
 	visitBlockStmt(*BlockStmt) uint8
+	visitBreakStmt(*BreakStmt) uint8
+	visitClassStmt(*ClassStmt) uint8
+	visitContinueStmt(*ContinueStmt) uint8
 	visitExpressionStmt(*ExpressionStmt) uint8
+	visitForInStmt(*ForInStmt) uint8
+	visitFunctionStmt(*FunctionStmt) uint8
 	visitIfStmt(*IfStmt) uint8
 	visitPrintStmt(*PrintStmt) uint8
+	visitReturnStmt(*ReturnStmt) uint8
+	visitThrowStmt(*ThrowStmt) uint8
+	visitTryStmt(*TryStmt) uint8
 	visitVarStmt(*VarStmt) uint8
 	visitWhileStmt(*WhileStmt) uint8
 }
 
 type visitorStmtUint16 interface {
 	visitBlockStmt(*BlockStmt) uint16
+	visitBreakStmt(*BreakStmt) uint16
+	visitClassStmt(*ClassStmt) uint16
+	visitContinueStmt(*ContinueStmt) uint16
 	visitExpressionStmt(*ExpressionStmt) uint16
+	visitForInStmt(*ForInStmt) uint16
+	visitFunctionStmt(*FunctionStmt) uint16
 	visitIfStmt(*IfStmt) uint16
 	visitPrintStmt(*PrintStmt) uint16
+	visitReturnStmt(*ReturnStmt) uint16
+	visitThrowStmt(*ThrowStmt) uint16
+	visitTryStmt(*TryStmt) uint16
 	visitVarStmt(*VarStmt) uint16
 	visitWhileStmt(*WhileStmt) uint16
 }
 
 type visitorStmtUint32 interface {
 	visitBlockStmt(*BlockStmt) uint32
+	visitBreakStmt(*BreakStmt) uint32
+	visitClassStmt(*ClassStmt) uint32
+	visitContinueStmt(*ContinueStmt) uint32
 	visitExpressionStmt(*ExpressionStmt) uint32
+	visitForInStmt(*ForInStmt) uint32
+	visitFunctionStmt(*FunctionStmt) uint32
 	visitIfStmt(*IfStmt) uint32
 	visitPrintStmt(*PrintStmt) uint32
+	visitReturnStmt(*ReturnStmt) uint32
+	visitThrowStmt(*ThrowStmt) uint32
+	visitTryStmt(*TryStmt) uint32
 	visitVarStmt(*VarStmt) uint32
 	visitWhileStmt(*WhileStmt) uint32
 }
 
 type visitorStmtUint64 interface {
 	visitBlockStmt(*BlockStmt) uint64
+	visitBreakStmt(*BreakStmt) uint64
+	visitClassStmt(*ClassStmt) uint64
+	visitContinueStmt(*ContinueStmt) uint64
 	visitExpressionStmt(*ExpressionStmt) uint64
+	visitForInStmt(*ForInStmt) uint64
+	visitFunctionStmt(*FunctionStmt) uint64
 	visitIfStmt(*IfStmt) uint64
 	visitPrintStmt(*PrintStmt) uint64
+	visitReturnStmt(*ReturnStmt) uint64
+	visitThrowStmt(*ThrowStmt) uint64
+	visitTryStmt(*TryStmt) uint64
 	visitVarStmt(*VarStmt) uint64
 	visitWhileStmt(*WhileStmt) uint64
 }
 
 type visitorStmtUintptr interface {
 	visitBlockStmt(*BlockStmt) uintptr
+	visitBreakStmt(*BreakStmt) uintptr
+	visitClassStmt(*ClassStmt) uintptr
+	visitContinueStmt(*ContinueStmt) uintptr
 	visitExpressionStmt(*ExpressionStmt) uintptr
+	visitForInStmt(*ForInStmt) uintptr
+	visitFunctionStmt(*FunctionStmt) uintptr
 	visitIfStmt(*IfStmt) uintptr
 	visitPrintStmt(*PrintStmt) uintptr
+	visitReturnStmt(*ReturnStmt) uintptr
+	visitThrowStmt(*ThrowStmt) uintptr
+	visitTryStmt(*TryStmt) uintptr
 	visitVarStmt(*VarStmt) uintptr
 	visitWhileStmt(*WhileStmt) uintptr
 }
 
 type visitorStmtByte interface {
 	visitBlockStmt(*BlockStmt) byte
+	visitBreakStmt(*BreakStmt) byte
+	visitClassStmt(*ClassStmt) byte
+	visitContinueStmt(*ContinueStmt) byte
 	visitExpressionStmt(*ExpressionStmt) byte
+	visitForInStmt(*ForInStmt) byte
+	visitFunctionStmt(*FunctionStmt) byte
 	visitIfStmt(*IfStmt) byte
 	visitPrintStmt(*PrintStmt) byte
+	visitReturnStmt(*ReturnStmt) byte
+	visitThrowStmt(*ThrowStmt) byte
+	visitTryStmt(*TryStmt) byte
 	visitVarStmt(*VarStmt) byte
 	visitWhileStmt(*WhileStmt) byte
 }
 
 type visitorStmtRune interface {
 	visitBlockStmt(*BlockStmt) rune
+	visitBreakStmt(*BreakStmt) rune
+	visitClassStmt(*ClassStmt) rune
+	visitContinueStmt(*ContinueStmt) rune
 	visitExpressionStmt(*ExpressionStmt) rune
+	visitForInStmt(*ForInStmt) rune
+	visitFunctionStmt(*FunctionStmt) rune
 	visitIfStmt(*IfStmt) rune
 	visitPrintStmt(*PrintStmt) rune
+	visitReturnStmt(*ReturnStmt) rune
+	visitThrowStmt(*ThrowStmt) rune
+	visitTryStmt(*TryStmt) rune
 	visitVarStmt(*VarStmt) rune
 	visitWhileStmt(*WhileStmt) rune
 }
 
 type visitorStmtFloat32 interface {
 	visitBlockStmt(*BlockStmt) float32
+	visitBreakStmt(*BreakStmt) float32
+	visitClassStmt(*ClassStmt) float32
+	visitContinueStmt(*ContinueStmt) float32
 	visitExpressionStmt(*ExpressionStmt) float32
+	visitForInStmt(*ForInStmt) float32
+	visitFunctionStmt(*FunctionStmt) float32
 	visitIfStmt(*IfStmt) float32
 	visitPrintStmt(*PrintStmt) float32
+	visitReturnStmt(*ReturnStmt) float32
+	visitThrowStmt(*ThrowStmt) float32
+	visitTryStmt(*TryStmt) float32
 	visitVarStmt(*VarStmt) float32
 	visitWhileStmt(*WhileStmt) float32
 }
 
 type visitorStmtFloat64 interface {
 	visitBlockStmt(*BlockStmt) float64
+	visitBreakStmt(*BreakStmt) float64
+	visitClassStmt(*ClassStmt) float64
+	visitContinueStmt(*ContinueStmt) float64
 	visitExpressionStmt(*ExpressionStmt) float64
+	visitForInStmt(*ForInStmt) float64
+	visitFunctionStmt(*FunctionStmt) float64
 	visitIfStmt(*IfStmt) float64
 	visitPrintStmt(*PrintStmt) float64
+	visitReturnStmt(*ReturnStmt) float64
+	visitThrowStmt(*ThrowStmt) float64
+	visitTryStmt(*TryStmt) float64
 	visitVarStmt(*VarStmt) float64
 	visitWhileStmt(*WhileStmt) float64
 }
 
 type visitorStmtComplex64 interface {
 	visitBlockStmt(*BlockStmt) complex64
+	visitBreakStmt(*BreakStmt) complex64
+	visitClassStmt(*ClassStmt) complex64
+	visitContinueStmt(*ContinueStmt) complex64
 	visitExpressionStmt(*ExpressionStmt) complex64
+	visitForInStmt(*ForInStmt) complex64
+	visitFunctionStmt(*FunctionStmt) complex64
 	visitIfStmt(*IfStmt) complex64
 	visitPrintStmt(*PrintStmt) complex64
+	visitReturnStmt(*ReturnStmt) complex64
+	visitThrowStmt(*ThrowStmt) complex64
+	visitTryStmt(*TryStmt) complex64
 	visitVarStmt(*VarStmt) complex64
 	visitWhileStmt(*WhileStmt) complex64
 }
 
 type visitorStmtComplex128 interface {
 	visitBlockStmt(*BlockStmt) complex128
+	visitBreakStmt(*BreakStmt) complex128
+	visitClassStmt(*ClassStmt) complex128
+	visitContinueStmt(*ContinueStmt) complex128
 	visitExpressionStmt(*ExpressionStmt) complex128
+	visitForInStmt(*ForInStmt) complex128
+	visitFunctionStmt(*FunctionStmt) complex128
 	visitIfStmt(*IfStmt) complex128
 	visitPrintStmt(*PrintStmt) complex128
+	visitReturnStmt(*ReturnStmt) complex128
+	visitThrowStmt(*ThrowStmt) complex128
+	visitTryStmt(*TryStmt) complex128
 	visitVarStmt(*VarStmt) complex128
 	visitWhileStmt(*WhileStmt) complex128
 }
 
 type BlockStmt struct {
 	statements []Stmt
+	span       Span
 }
 
 // BlockStmt implements Stmt
@@ -217,6 +381,14 @@ func NewBlockStmt(statements []Stmt) *BlockStmt {
 	}
 }
 
+func (expr *BlockStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *BlockStmt) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *BlockStmt) Accept(v visitorStmt) interface{} {
 	return v.visitBlockStmt(expr)
 }
@@ -297,8 +469,319 @@ func (expr *BlockStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
 	return v.visitBlockStmt(expr)
 }
 
+type BreakStmt struct {
+	keyword Token
+	span    Span
+}
+
+// BreakStmt implements Stmt
+var _ Stmt = &BreakStmt{}
+
+func NewBreakStmt(keyword Token) *BreakStmt {
+	return &BreakStmt{
+		keyword: keyword,
+	}
+}
+
+func (expr *BreakStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *BreakStmt) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *BreakStmt) Accept(v visitorStmt) interface{} {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptBool(v visitorStmtBool) bool {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptString(v visitorStmtString) string {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptInt(v visitorStmtInt) int {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptInt8(v visitorStmtInt8) int8 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptInt16(v visitorStmtInt16) int16 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptInt32(v visitorStmtInt32) int32 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptInt64(v visitorStmtInt64) int64 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptUint(v visitorStmtUint) uint {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptUint8(v visitorStmtUint8) uint8 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptUint16(v visitorStmtUint16) uint16 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptUint32(v visitorStmtUint32) uint32 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptUint64(v visitorStmtUint64) uint64 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptUintptr(v visitorStmtUintptr) uintptr {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptByte(v visitorStmtByte) byte {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptRune(v visitorStmtRune) rune {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptFloat32(v visitorStmtFloat32) float32 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptFloat64(v visitorStmtFloat64) float64 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptComplex64(v visitorStmtComplex64) complex64 {
+	return v.visitBreakStmt(expr)
+}
+
+func (expr *BreakStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
+	return v.visitBreakStmt(expr)
+}
+
+type ClassStmt struct {
+	name       Token
+	superclass *VariableExpr
+	methods    []*FunctionStmt
+	span       Span
+}
+
+// ClassStmt implements Stmt
+var _ Stmt = &ClassStmt{}
+
+func NewClassStmt(name Token, superclass *VariableExpr, methods []*FunctionStmt) *ClassStmt {
+	return &ClassStmt{
+		name:       name,
+		superclass: superclass,
+		methods:    methods,
+	}
+}
+
+func (expr *ClassStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *ClassStmt) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *ClassStmt) Accept(v visitorStmt) interface{} {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptBool(v visitorStmtBool) bool {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptString(v visitorStmtString) string {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptInt(v visitorStmtInt) int {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptInt8(v visitorStmtInt8) int8 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptInt16(v visitorStmtInt16) int16 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptInt32(v visitorStmtInt32) int32 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptInt64(v visitorStmtInt64) int64 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptUint(v visitorStmtUint) uint {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptUint8(v visitorStmtUint8) uint8 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptUint16(v visitorStmtUint16) uint16 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptUint32(v visitorStmtUint32) uint32 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptUint64(v visitorStmtUint64) uint64 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptUintptr(v visitorStmtUintptr) uintptr {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptByte(v visitorStmtByte) byte {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptRune(v visitorStmtRune) rune {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptFloat32(v visitorStmtFloat32) float32 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptFloat64(v visitorStmtFloat64) float64 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptComplex64(v visitorStmtComplex64) complex64 {
+	return v.visitClassStmt(expr)
+}
+
+func (expr *ClassStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
+	return v.visitClassStmt(expr)
+}
+
+type ContinueStmt struct {
+	keyword Token
+	span    Span
+}
+
+// ContinueStmt implements Stmt
+var _ Stmt = &ContinueStmt{}
+
+func NewContinueStmt(keyword Token) *ContinueStmt {
+	return &ContinueStmt{
+		keyword: keyword,
+	}
+}
+
+func (expr *ContinueStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *ContinueStmt) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *ContinueStmt) Accept(v visitorStmt) interface{} {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptBool(v visitorStmtBool) bool {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptString(v visitorStmtString) string {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptInt(v visitorStmtInt) int {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptInt8(v visitorStmtInt8) int8 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptInt16(v visitorStmtInt16) int16 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptInt32(v visitorStmtInt32) int32 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptInt64(v visitorStmtInt64) int64 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptUint(v visitorStmtUint) uint {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptUint8(v visitorStmtUint8) uint8 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptUint16(v visitorStmtUint16) uint16 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptUint32(v visitorStmtUint32) uint32 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptUint64(v visitorStmtUint64) uint64 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptUintptr(v visitorStmtUintptr) uintptr {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptByte(v visitorStmtByte) byte {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptRune(v visitorStmtRune) rune {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptFloat32(v visitorStmtFloat32) float32 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptFloat64(v visitorStmtFloat64) float64 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptComplex64(v visitorStmtComplex64) complex64 {
+	return v.visitContinueStmt(expr)
+}
+
+func (expr *ContinueStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
+	return v.visitContinueStmt(expr)
+}
+
 type ExpressionStmt struct {
 	expression Expr
+	span       Span
 }
 
 // ExpressionStmt implements Stmt
@@ -310,6 +793,14 @@ func NewExpressionStmt(expression Expr) *ExpressionStmt {
 	}
 }
 
+func (expr *ExpressionStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *ExpressionStmt) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *ExpressionStmt) Accept(v visitorStmt) interface{} {
 	return v.visitExpressionStmt(expr)
 }
@@ -390,10 +881,227 @@ func (expr *ExpressionStmt) AcceptComplex128(v visitorStmtComplex128) complex128
 	return v.visitExpressionStmt(expr)
 }
 
+type ForInStmt struct {
+	keyword  Token
+	name     Token
+	in       Token
+	iterable Expr
+	body     Stmt
+	span     Span
+}
+
+// ForInStmt implements Stmt
+var _ Stmt = &ForInStmt{}
+
+func NewForInStmt(keyword Token, name Token, in Token, iterable Expr, body Stmt) *ForInStmt {
+	return &ForInStmt{
+		keyword:  keyword,
+		name:     name,
+		in:       in,
+		iterable: iterable,
+		body:     body,
+	}
+}
+
+func (expr *ForInStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *ForInStmt) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *ForInStmt) Accept(v visitorStmt) interface{} {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptBool(v visitorStmtBool) bool {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptString(v visitorStmtString) string {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptInt(v visitorStmtInt) int {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptInt8(v visitorStmtInt8) int8 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptInt16(v visitorStmtInt16) int16 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptInt32(v visitorStmtInt32) int32 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptInt64(v visitorStmtInt64) int64 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptUint(v visitorStmtUint) uint {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptUint8(v visitorStmtUint8) uint8 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptUint16(v visitorStmtUint16) uint16 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptUint32(v visitorStmtUint32) uint32 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptUint64(v visitorStmtUint64) uint64 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptUintptr(v visitorStmtUintptr) uintptr {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptByte(v visitorStmtByte) byte {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptRune(v visitorStmtRune) rune {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptFloat32(v visitorStmtFloat32) float32 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptFloat64(v visitorStmtFloat64) float64 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptComplex64(v visitorStmtComplex64) complex64 {
+	return v.visitForInStmt(expr)
+}
+
+func (expr *ForInStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
+	return v.visitForInStmt(expr)
+}
+
+type FunctionStmt struct {
+	name   Token
+	params []Token
+	body   []Stmt
+	span   Span
+}
+
+// FunctionStmt implements Stmt
+var _ Stmt = &FunctionStmt{}
+
+func NewFunctionStmt(name Token, params []Token, body []Stmt) *FunctionStmt {
+	return &FunctionStmt{
+		name:   name,
+		params: params,
+		body:   body,
+	}
+}
+
+func (expr *FunctionStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *FunctionStmt) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *FunctionStmt) Accept(v visitorStmt) interface{} {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptBool(v visitorStmtBool) bool {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptString(v visitorStmtString) string {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptInt(v visitorStmtInt) int {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptInt8(v visitorStmtInt8) int8 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptInt16(v visitorStmtInt16) int16 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptInt32(v visitorStmtInt32) int32 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptInt64(v visitorStmtInt64) int64 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptUint(v visitorStmtUint) uint {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptUint8(v visitorStmtUint8) uint8 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptUint16(v visitorStmtUint16) uint16 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptUint32(v visitorStmtUint32) uint32 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptUint64(v visitorStmtUint64) uint64 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptUintptr(v visitorStmtUintptr) uintptr {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptByte(v visitorStmtByte) byte {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptRune(v visitorStmtRune) rune {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptFloat32(v visitorStmtFloat32) float32 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptFloat64(v visitorStmtFloat64) float64 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptComplex64(v visitorStmtComplex64) complex64 {
+	return v.visitFunctionStmt(expr)
+}
+
+func (expr *FunctionStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
+	return v.visitFunctionStmt(expr)
+}
+
 type IfStmt struct {
 	condition  Expr
 	thenBranch Stmt
 	elseBranch Stmt
+	span       Span
 }
 
 // IfStmt implements Stmt
@@ -407,6 +1115,14 @@ func NewIfStmt(condition Expr, thenBranch Stmt, elseBranch Stmt) *IfStmt {
 	}
 }
 
+func (expr *IfStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *IfStmt) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *IfStmt) Accept(v visitorStmt) interface{} {
 	return v.visitIfStmt(expr)
 }
@@ -489,6 +1205,7 @@ func (expr *IfStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
 
 type PrintStmt struct {
 	expression Expr
+	span       Span
 }
 
 // PrintStmt implements Stmt
@@ -500,6 +1217,14 @@ func NewPrintStmt(expression Expr) *PrintStmt {
 	}
 }
 
+func (expr *PrintStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *PrintStmt) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *PrintStmt) Accept(v visitorStmt) interface{} {
 	return v.visitPrintStmt(expr)
 }
@@ -580,9 +1305,328 @@ func (expr *PrintStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
 	return v.visitPrintStmt(expr)
 }
 
+type ReturnStmt struct {
+	keyword Token
+	value   Expr
+	span    Span
+}
+
+// ReturnStmt implements Stmt
+var _ Stmt = &ReturnStmt{}
+
+func NewReturnStmt(keyword Token, value Expr) *ReturnStmt {
+	return &ReturnStmt{
+		keyword: keyword,
+		value:   value,
+	}
+}
+
+func (expr *ReturnStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *ReturnStmt) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *ReturnStmt) Accept(v visitorStmt) interface{} {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptBool(v visitorStmtBool) bool {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptString(v visitorStmtString) string {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptInt(v visitorStmtInt) int {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptInt8(v visitorStmtInt8) int8 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptInt16(v visitorStmtInt16) int16 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptInt32(v visitorStmtInt32) int32 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptInt64(v visitorStmtInt64) int64 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptUint(v visitorStmtUint) uint {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptUint8(v visitorStmtUint8) uint8 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptUint16(v visitorStmtUint16) uint16 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptUint32(v visitorStmtUint32) uint32 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptUint64(v visitorStmtUint64) uint64 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptUintptr(v visitorStmtUintptr) uintptr {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptByte(v visitorStmtByte) byte {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptRune(v visitorStmtRune) rune {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptFloat32(v visitorStmtFloat32) float32 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptFloat64(v visitorStmtFloat64) float64 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptComplex64(v visitorStmtComplex64) complex64 {
+	return v.visitReturnStmt(expr)
+}
+
+func (expr *ReturnStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
+	return v.visitReturnStmt(expr)
+}
+
+type ThrowStmt struct {
+	keyword Token
+	value   Expr
+	span    Span
+}
+
+// ThrowStmt implements Stmt
+var _ Stmt = &ThrowStmt{}
+
+func NewThrowStmt(keyword Token, value Expr) *ThrowStmt {
+	return &ThrowStmt{
+		keyword: keyword,
+		value:   value,
+	}
+}
+
+func (expr *ThrowStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *ThrowStmt) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *ThrowStmt) Accept(v visitorStmt) interface{} {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptBool(v visitorStmtBool) bool {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptString(v visitorStmtString) string {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptInt(v visitorStmtInt) int {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptInt8(v visitorStmtInt8) int8 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptInt16(v visitorStmtInt16) int16 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptInt32(v visitorStmtInt32) int32 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptInt64(v visitorStmtInt64) int64 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptUint(v visitorStmtUint) uint {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptUint8(v visitorStmtUint8) uint8 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptUint16(v visitorStmtUint16) uint16 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptUint32(v visitorStmtUint32) uint32 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptUint64(v visitorStmtUint64) uint64 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptUintptr(v visitorStmtUintptr) uintptr {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptByte(v visitorStmtByte) byte {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptRune(v visitorStmtRune) rune {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptFloat32(v visitorStmtFloat32) float32 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptFloat64(v visitorStmtFloat64) float64 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptComplex64(v visitorStmtComplex64) complex64 {
+	return v.visitThrowStmt(expr)
+}
+
+func (expr *ThrowStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
+	return v.visitThrowStmt(expr)
+}
+
+type TryStmt struct {
+	keyword     Token
+	body        []Stmt
+	catchName   Token
+	catchBody   []Stmt
+	finallyBody []Stmt
+	span        Span
+}
+
+// TryStmt implements Stmt
+var _ Stmt = &TryStmt{}
+
+func NewTryStmt(keyword Token, body []Stmt, catchName Token, catchBody []Stmt, finallyBody []Stmt) *TryStmt {
+	return &TryStmt{
+		keyword:     keyword,
+		body:        body,
+		catchName:   catchName,
+		catchBody:   catchBody,
+		finallyBody: finallyBody,
+	}
+}
+
+func (expr *TryStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *TryStmt) setSpan(span Span) {
+	expr.span = span
+}
+
+func (expr *TryStmt) Accept(v visitorStmt) interface{} {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptBool(v visitorStmtBool) bool {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptString(v visitorStmtString) string {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptInt(v visitorStmtInt) int {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptInt8(v visitorStmtInt8) int8 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptInt16(v visitorStmtInt16) int16 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptInt32(v visitorStmtInt32) int32 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptInt64(v visitorStmtInt64) int64 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptUint(v visitorStmtUint) uint {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptUint8(v visitorStmtUint8) uint8 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptUint16(v visitorStmtUint16) uint16 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptUint32(v visitorStmtUint32) uint32 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptUint64(v visitorStmtUint64) uint64 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptUintptr(v visitorStmtUintptr) uintptr {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptByte(v visitorStmtByte) byte {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptRune(v visitorStmtRune) rune {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptFloat32(v visitorStmtFloat32) float32 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptFloat64(v visitorStmtFloat64) float64 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptComplex64(v visitorStmtComplex64) complex64 {
+	return v.visitTryStmt(expr)
+}
+
+func (expr *TryStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
+	return v.visitTryStmt(expr)
+}
+
 type VarStmt struct {
 	name        Token
 	initializer Expr
+	span        Span
 }
 
 // VarStmt implements Stmt
@@ -595,6 +1639,14 @@ func NewVarStmt(name Token, initializer Expr) *VarStmt {
 	}
 }
 
+func (expr *VarStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *VarStmt) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *VarStmt) Accept(v visitorStmt) interface{} {
 	return v.visitVarStmt(expr)
 }
@@ -676,20 +1728,33 @@ func (expr *VarStmt) AcceptComplex128(v visitorStmtComplex128) complex128 {
 }
 
 type WhileStmt struct {
+	keyword   Token
 	condition Expr
 	body      Stmt
+	increment Expr
+	span      Span
 }
 
 // WhileStmt implements Stmt
 var _ Stmt = &WhileStmt{}
 
-func NewWhileStmt(condition Expr, body Stmt) *WhileStmt {
+func NewWhileStmt(keyword Token, condition Expr, body Stmt, increment Expr) *WhileStmt {
 	return &WhileStmt{
+		keyword:   keyword,
 		condition: condition,
 		body:      body,
+		increment: increment,
 	}
 }
 
+func (expr *WhileStmt) Span() Span {
+	return expr.span
+}
+
+func (expr *WhileStmt) setSpan(span Span) {
+	expr.span = span
+}
+
 func (expr *WhileStmt) Accept(v visitorStmt) interface{} {
 	return v.visitWhileStmt(expr)
 }
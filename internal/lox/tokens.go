@@ -9,6 +9,9 @@ const (
 	RightParen
 	LeftBrace
 	RightBrace
+	LeftBracket
+	RightBracket
+	Colon
 	Comma
 	Dot
 	Minus
@@ -31,19 +34,26 @@ const (
 	Number
 	// Keywords
 	And
+	Break
+	Catch
 	Class
+	Continue
 	Else
 	False
+	Finally
 	Fun
 	For
 	If
+	In
 	Nil
 	Or
 	Print
 	Return
 	Super
 	This
+	Throw
 	True
+	Try
 	Var
 	While
 	// EOF
@@ -56,6 +66,13 @@ type Token struct {
 	Lexeme  string
 	Literal interface{}
 	Line    int
+	// Column is the column of the first byte of the token on its line, starting at 1.
+	Column int
+	// Offset is the byte offset of the token in the source and Length its number of bytes.
+	Offset int
+	Length int
+	// File is the name of the source file the token comes from, if any.
+	File string
 }
 
 func NewToken(tokenType TokenType, lexeme string, literal interface{}, line int) Token {
@@ -66,3 +83,8 @@ func NewToken(tokenType TokenType, lexeme string, literal interface{}, line int)
 		Line:    line,
 	}
 }
+
+// Span returns the location of the token in the source.
+func (t Token) Span() Span {
+	return Span{File: t.File, Line: t.Line, Column: t.Column, Offset: t.Offset, Length: t.Length}
+}
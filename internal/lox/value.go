@@ -0,0 +1,84 @@
+package lox
+
+import "fmt"
+
+type valueType byte
+
+const (
+	valueNil valueType = iota
+	valueBool
+	valueNumber
+	valueObj
+)
+
+// value is a value of the bytecode virtual machine. Unlike the values of the tree-walk interpreter, it is
+// not boxed in an interface: numbers and booleans live in the struct itself and only heap objects are
+// referenced through obj.
+type value struct {
+	typ     valueType
+	boolean bool
+	number  float64
+	obj     obj
+}
+
+var nilValue = value{typ: valueNil}
+
+func boolValue(b bool) value {
+	return value{typ: valueBool, boolean: b}
+}
+
+func numberValue(n float64) value {
+	return value{typ: valueNumber, number: n}
+}
+
+func objValue(o obj) value {
+	return value{typ: valueObj, obj: o}
+}
+
+func (v value) isFalsey() bool {
+	return v.typ == valueNil || (v.typ == valueBool && !v.boolean)
+}
+
+// asString returns the string held by the value, if it is one.
+func (v value) asString() (*objString, bool) {
+	if v.typ != valueObj {
+		return nil, false
+	}
+	s, ok := v.obj.(*objString)
+	return s, ok
+}
+
+// valuesEqual follows the semantics of isEqual in the tree-walk interpreter: strings are compared by
+// content and other objects by identity.
+func valuesEqual(a, b value) bool {
+	if a.typ != b.typ {
+		return false
+	}
+	switch a.typ {
+	case valueNil:
+		return true
+	case valueBool:
+		return a.boolean == b.boolean
+	case valueNumber:
+		return a.number == b.number
+	}
+	aString, ok := a.obj.(*objString)
+	if ok {
+		bString, ok := b.obj.(*objString)
+		return ok && aString.chars == bString.chars
+	}
+	return a.obj == b.obj
+}
+
+// String formats the value the same way the tree-walk interpreter prints it.
+func (v value) String() string {
+	switch v.typ {
+	case valueNil:
+		return "nil"
+	case valueBool:
+		return fmt.Sprintf("%v", v.boolean)
+	case valueNumber:
+		return fmt.Sprintf("%v", v.number)
+	}
+	return v.obj.String()
+}
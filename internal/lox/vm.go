@@ -0,0 +1,564 @@
+package lox
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// framesInitial and stackInitial are the capacities the call frames and the stack start with. Both grow
+	// as needed, up to the maximum call depth of the virtual machine.
+	framesInitial = 64
+	stackInitial  = framesInitial * maxLocals
+)
+
+type callFrame struct {
+	closure *objClosure
+	// ip is the index in the chunk of the next instruction to execute.
+	ip int
+	// slots is the index in the VM stack of the first slot the function can use.
+	slots int
+}
+
+func (f *callFrame) readByte() byte {
+	b := f.closure.function.chunk.code[f.ip]
+	f.ip++
+	return b
+}
+
+func (f *callFrame) readShort() int {
+	code := f.closure.function.chunk.code
+	s := int(code[f.ip])<<8 | int(code[f.ip+1])
+	f.ip += 2
+	return s
+}
+
+func (f *callFrame) readConstant() value {
+	return f.closure.function.chunk.constants[f.readShort()]
+}
+
+func (f *callFrame) readString() string {
+	return f.readConstant().obj.(*objString).chars
+}
+
+// vm is a stack-based virtual machine that runs the bytecode produced by the compiler. The objects it
+// allocates are managed by its own garbage collector.
+type vm struct {
+	frames  []callFrame
+	stack   []value
+	globals map[string]value
+	// openUpvalues is the list of upvalues that still point to the stack, sorted by decreasing slot.
+	openUpvalues *objUpvalue
+
+	// objects is the list of every object allocated by the virtual machine.
+	objects obj
+	// strings interns the strings created at runtime. It holds weak references: the garbage collector
+	// removes the strings that are not reachable otherwise.
+	strings map[string]*objString
+	// grayStack holds the objects marked by the garbage collector whose references are not marked yet.
+	grayStack        []obj
+	bytesAllocated   int
+	nextGC           int
+	gcHeapGrowFactor float64
+	stressGC         bool
+	gcLog            io.Writer
+	gcStats          GCStats
+
+	// maxCallDepth is the number of nested calls allowed, or 0 for no limit.
+	maxCallDepth int
+
+	// output is where print statements write.
+	output io.Writer
+	// errorOutput is where runtime errors are reported. When it is nil, errors are only returned.
+	errorOutput io.Writer
+}
+
+// WithVMMaxCallDepth limits the number of nested calls. A call exceeding it stops the script with a "Stack
+// overflow." runtime error. It defaults to 10000, like the tree-walk interpreter, and 0 removes the limit.
+func WithVMMaxCallDepth(depth int) VMOption {
+	return func(vm *vm) {
+		vm.maxCallDepth = depth
+	}
+}
+
+// WithVMOutput sets where print statements write. It defaults to stdout.
+func WithVMOutput(w io.Writer) VMOption {
+	return func(vm *vm) {
+		vm.output = w
+	}
+}
+
+// WithVMErrorOutput sets where runtime errors are reported. By default, errors are only returned.
+func WithVMErrorOutput(w io.Writer) VMOption {
+	return func(vm *vm) {
+		vm.errorOutput = w
+	}
+}
+
+func NewVM(options ...VMOption) *vm {
+	machine := &vm{
+		frames:           make([]callFrame, 0, framesInitial),
+		stack:            make([]value, 0, stackInitial),
+		globals:          make(map[string]value),
+		strings:          make(map[string]*objString),
+		grayStack:        make([]obj, 0),
+		nextGC:           defaultGCInitialHeap,
+		gcHeapGrowFactor: defaultGCHeapGrowFactor,
+		maxCallDepth:     defaultMaxCallDepth,
+		output:           os.Stdout,
+	}
+	for _, option := range options {
+		option(machine)
+	}
+	machine.defineNative("clock", 0, func(vm *vm, arguments []value) (value, error) {
+		return numberValue(float64(time.Now().UnixNano()) / float64(time.Second)), nil
+	})
+	return machine
+}
+
+// Interpret runs the function of a compiled script until the end or until a runtime error, which is
+// reported on the error output, if any, and returned.
+func (vm *vm) Interpret(function *objFunction) error {
+	closure := newObjClosure(function)
+	vm.allocate(closure)
+	vm.push(objValue(closure))
+	err := vm.call(closure, 0)
+	if err == nil {
+		err = vm.run()
+	}
+	if err != nil {
+		vm.resetStack()
+		if vm.errorOutput != nil {
+			fmt.Fprintln(vm.errorOutput, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (vm *vm) defineNative(name string, arity int, function nativeFn) {
+	native := &objNative{name: name, arity: arity, function: function}
+	vm.allocate(native)
+	vm.globals[name] = objValue(native)
+}
+
+func (vm *vm) resetStack() {
+	vm.stack = vm.stack[:0]
+	vm.frames = vm.frames[:0]
+	vm.openUpvalues = nil
+}
+
+func (vm *vm) push(v value) {
+	vm.stack = append(vm.stack, v)
+}
+
+func (vm *vm) pop() value {
+	v := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+	return v
+}
+
+// peek returns the value distance slots down from the top of the stack.
+func (vm *vm) peek(distance int) value {
+	return vm.stack[len(vm.stack)-1-distance]
+}
+
+func (vm *vm) run() *vmRuntimeError {
+	frame := &vm.frames[len(vm.frames)-1]
+	for {
+		switch opCode(frame.readByte()) {
+		case opConstant:
+			vm.push(frame.readConstant())
+		case opNil:
+			vm.push(nilValue)
+		case opTrue:
+			vm.push(boolValue(true))
+		case opFalse:
+			vm.push(boolValue(false))
+		case opPop:
+			vm.pop()
+		case opGetLocal:
+			slot := int(frame.readByte())
+			vm.push(vm.stack[frame.slots+slot])
+		case opSetLocal:
+			slot := int(frame.readByte())
+			vm.stack[frame.slots+slot] = vm.peek(0)
+		case opGetGlobal:
+			name := frame.readString()
+			value, ok := vm.globals[name]
+			if !ok {
+				return vm.runtimeError("Undefined variable '%s'.", name)
+			}
+			vm.push(value)
+		case opDefineGlobal:
+			name := frame.readString()
+			vm.globals[name] = vm.pop()
+		case opSetGlobal:
+			name := frame.readString()
+			if _, ok := vm.globals[name]; !ok {
+				return vm.runtimeError("Undefined variable '%s'.", name)
+			}
+			vm.globals[name] = vm.peek(0)
+		case opGetUpvalue:
+			upvalue := frame.closure.upvalues[frame.readByte()]
+			if upvalue.isClosed {
+				vm.push(upvalue.closed)
+			} else {
+				vm.push(vm.stack[upvalue.slot])
+			}
+		case opSetUpvalue:
+			upvalue := frame.closure.upvalues[frame.readByte()]
+			if upvalue.isClosed {
+				upvalue.closed = vm.peek(0)
+			} else {
+				vm.stack[upvalue.slot] = vm.peek(0)
+			}
+		case opGetProperty:
+			name := frame.readString()
+			instance, ok := vm.peek(0).obj.(*objInstance)
+			if !ok {
+				return vm.runtimeError("Only instances have properties.")
+			}
+			if value, ok := instance.fields[name]; ok {
+				vm.pop()
+				vm.push(value)
+				break
+			}
+			if err := vm.bindMethod(instance.class, name); err != nil {
+				return err
+			}
+		case opSetProperty:
+			name := frame.readString()
+			instance, ok := vm.peek(1).obj.(*objInstance)
+			if !ok {
+				return vm.runtimeError("Only instances have fields.")
+			}
+			value := vm.pop()
+			if _, ok := instance.fields[name]; !ok {
+				vm.grow(mapEntrySize)
+			}
+			instance.fields[name] = value
+			vm.pop()
+			vm.push(value)
+		case opGetSuper:
+			name := frame.readString()
+			superclass, ok := vm.pop().obj.(*objClass)
+			if !ok {
+				return vm.runtimeError("Superclass must be a class.")
+			}
+			if err := vm.bindMethod(superclass, name); err != nil {
+				return err
+			}
+		case opEqual:
+			b := vm.pop()
+			a := vm.pop()
+			vm.push(boolValue(valuesEqual(a, b)))
+		case opNotEqual:
+			b := vm.pop()
+			a := vm.pop()
+			vm.push(boolValue(!valuesEqual(a, b)))
+		case opGreater, opGreaterEqual, opLess, opLessEqual, opSubtract, opMultiply, opDivide:
+			if err := vm.binaryNumberOp(opCode(frame.closure.function.chunk.code[frame.ip-1])); err != nil {
+				return err
+			}
+		case opAdd:
+			b := vm.peek(0)
+			a := vm.peek(1)
+			if a.typ == valueNumber && b.typ == valueNumber {
+				vm.pop()
+				vm.stack[len(vm.stack)-1] = numberValue(a.number + b.number)
+				break
+			}
+			aString, aOk := a.asString()
+			bString, bOk := b.asString()
+			if !aOk || !bOk {
+				return vm.runtimeError("Operands must be two numbers or two strings.")
+			}
+			// the operands stay on the stack while the result is allocated so that they remain reachable
+			result := vm.newString(aString.chars + bString.chars)
+			vm.pop()
+			vm.stack[len(vm.stack)-1] = objValue(result)
+		case opNot:
+			vm.push(boolValue(vm.pop().isFalsey()))
+		case opNegate:
+			if vm.peek(0).typ != valueNumber {
+				return vm.runtimeError("Operand must be a number.")
+			}
+			vm.push(numberValue(-vm.pop().number))
+		case opPrint:
+			fmt.Fprintln(vm.output, vm.pop().String())
+		case opJump:
+			offset := frame.readShort()
+			frame.ip += offset
+		case opJumpIfFalse:
+			offset := frame.readShort()
+			if vm.peek(0).isFalsey() {
+				frame.ip += offset
+			}
+		case opLoop:
+			offset := frame.readShort()
+			frame.ip -= offset
+		case opCall:
+			argCount := int(frame.readByte())
+			if err := vm.callValue(vm.peek(argCount), argCount); err != nil {
+				return err
+			}
+			frame = &vm.frames[len(vm.frames)-1]
+		case opInvoke:
+			method := frame.readString()
+			argCount := int(frame.readByte())
+			if err := vm.invoke(method, argCount); err != nil {
+				return err
+			}
+			frame = &vm.frames[len(vm.frames)-1]
+		case opSuperInvoke:
+			method := frame.readString()
+			argCount := int(frame.readByte())
+			superclass, ok := vm.pop().obj.(*objClass)
+			if !ok {
+				return vm.runtimeError("Superclass must be a class.")
+			}
+			if err := vm.invokeFromClass(superclass, method, argCount); err != nil {
+				return err
+			}
+			frame = &vm.frames[len(vm.frames)-1]
+		case opClosure:
+			function := frame.readConstant().obj.(*objFunction)
+			closure := newObjClosure(function)
+			vm.allocate(closure)
+			vm.push(objValue(closure))
+			for i := range closure.upvalues {
+				isLocal := frame.readByte()
+				index := int(frame.readByte())
+				if isLocal == 1 {
+					closure.upvalues[i] = vm.captureUpvalue(frame.slots + index)
+				} else {
+					closure.upvalues[i] = frame.closure.upvalues[index]
+				}
+			}
+		case opCloseUpvalue:
+			vm.closeUpvalues(len(vm.stack) - 1)
+			vm.pop()
+		case opReturn:
+			result := vm.pop()
+			vm.closeUpvalues(frame.slots)
+			vm.frames = vm.frames[:len(vm.frames)-1]
+			vm.stack = vm.stack[:frame.slots]
+			if len(vm.frames) == 0 {
+				// the script function is popped too
+				return nil
+			}
+			vm.push(result)
+			frame = &vm.frames[len(vm.frames)-1]
+		case opClass:
+			class := newObjClass(frame.readString())
+			vm.allocate(class)
+			vm.push(objValue(class))
+		case opInherit:
+			superclass, ok := vm.peek(1).obj.(*objClass)
+			if !ok {
+				return vm.runtimeError("Superclass must be a class.")
+			}
+			subclass, ok := vm.peek(0).obj.(*objClass)
+			if !ok {
+				return vm.runtimeError("Only classes can inherit.")
+			}
+			// copy the methods down so that method lookup does not walk the superclass chain at runtime
+			for name, method := range superclass.methods {
+				subclass.methods[name] = method
+			}
+			vm.grow(len(superclass.methods) * mapEntrySize)
+			vm.pop()
+		case opMethod:
+			name := frame.readString()
+			method, methodOk := vm.peek(0).obj.(*objClosure)
+			class, classOk := vm.peek(1).obj.(*objClass)
+			if !methodOk || !classOk {
+				return vm.runtimeError("Only functions can be methods of classes.")
+			}
+			if _, ok := class.methods[name]; !ok {
+				vm.grow(mapEntrySize)
+			}
+			class.methods[name] = method
+			vm.pop()
+		}
+	}
+}
+
+func (vm *vm) binaryNumberOp(op opCode) *vmRuntimeError {
+	b := vm.peek(0)
+	a := vm.peek(1)
+	if a.typ != valueNumber || b.typ != valueNumber {
+		return vm.runtimeError("Operands must be numbers.")
+	}
+	var result value
+	switch op {
+	case opGreater:
+		result = boolValue(a.number > b.number)
+	case opGreaterEqual:
+		result = boolValue(a.number >= b.number)
+	case opLess:
+		result = boolValue(a.number < b.number)
+	case opLessEqual:
+		result = boolValue(a.number <= b.number)
+	case opSubtract:
+		result = numberValue(a.number - b.number)
+	case opMultiply:
+		result = numberValue(a.number * b.number)
+	case opDivide:
+		if b.number == 0 {
+			return vm.runtimeError("Right operand must not be 0.")
+		}
+		result = numberValue(a.number / b.number)
+	}
+	vm.pop()
+	vm.stack[len(vm.stack)-1] = result
+	return nil
+}
+
+func (vm *vm) callValue(callee value, argCount int) *vmRuntimeError {
+	switch callee := callee.obj.(type) {
+	case *objClosure:
+		return vm.call(callee, argCount)
+	case *objNative:
+		if callee.arity != argCount {
+			return vm.runtimeError("Expected %d arguments but got %d.", callee.arity, argCount)
+		}
+		arguments := vm.stack[len(vm.stack)-argCount:]
+		result, err := callee.function(vm, arguments)
+		if err != nil {
+			return vm.runtimeError("%s", err.Error())
+		}
+		vm.stack = vm.stack[:len(vm.stack)-argCount-1]
+		vm.push(result)
+		return nil
+	case *objClass:
+		// the instance replaces the class on the stack and becomes "this" in the initializer
+		instance := newObjInstance(callee)
+		vm.allocate(instance)
+		vm.stack[len(vm.stack)-argCount-1] = objValue(instance)
+		if initializer, ok := callee.methods["init"]; ok {
+			return vm.call(initializer, argCount)
+		}
+		if argCount != 0 {
+			return vm.runtimeError("Expected 0 arguments but got %d.", argCount)
+		}
+		return nil
+	case *objBoundMethod:
+		vm.stack[len(vm.stack)-argCount-1] = callee.receiver
+		return vm.call(callee.method, argCount)
+	}
+	return vm.runtimeError("Can only call functions and classes.")
+}
+
+func (vm *vm) call(closure *objClosure, argCount int) *vmRuntimeError {
+	if argCount != closure.function.arity {
+		return vm.runtimeError("Expected %d arguments but got %d.", closure.function.arity, argCount)
+	}
+	// the first frame is the script's, which isn't a call
+	if vm.maxCallDepth > 0 && len(vm.frames) > vm.maxCallDepth {
+		return vm.runtimeError("Stack overflow.")
+	}
+	vm.frames = append(vm.frames, callFrame{
+		closure: closure,
+		ip:      0,
+		slots:   len(vm.stack) - argCount - 1,
+	})
+	return nil
+}
+
+func (vm *vm) invoke(name string, argCount int) *vmRuntimeError {
+	receiver := vm.peek(argCount)
+	instance, ok := receiver.obj.(*objInstance)
+	if !ok {
+		return vm.runtimeError("Only instances have properties.")
+	}
+	// a field holding a function shadows a method
+	if value, ok := instance.fields[name]; ok {
+		vm.stack[len(vm.stack)-argCount-1] = value
+		return vm.callValue(value, argCount)
+	}
+	return vm.invokeFromClass(instance.class, name, argCount)
+}
+
+func (vm *vm) invokeFromClass(class *objClass, name string, argCount int) *vmRuntimeError {
+	method, ok := class.methods[name]
+	if !ok {
+		return vm.runtimeError("Undefined property '%s'.", name)
+	}
+	return vm.call(method, argCount)
+}
+
+// bindMethod replaces the instance on top of the stack with the method of its class bound to it.
+func (vm *vm) bindMethod(class *objClass, name string) *vmRuntimeError {
+	method, ok := class.methods[name]
+	if !ok {
+		return vm.runtimeError("Undefined property '%s'.", name)
+	}
+	bound := &objBoundMethod{receiver: vm.peek(0), method: method}
+	vm.allocate(bound)
+	vm.pop()
+	vm.push(objValue(bound))
+	return nil
+}
+
+// captureUpvalue returns the open upvalue for the given stack slot, creating it if no closure captured
+// the slot yet.
+func (vm *vm) captureUpvalue(slot int) *objUpvalue {
+	var previous *objUpvalue = nil
+	upvalue := vm.openUpvalues
+	for upvalue != nil && upvalue.slot > slot {
+		previous = upvalue
+		upvalue = upvalue.next
+	}
+	if upvalue != nil && upvalue.slot == slot {
+		return upvalue
+	}
+	created := &objUpvalue{slot: slot, next: upvalue}
+	vm.allocate(created)
+	if previous == nil {
+		vm.openUpvalues = created
+	} else {
+		previous.next = created
+	}
+	return created
+}
+
+// closeUpvalues closes every open upvalue pointing to the given stack slot or above it.
+func (vm *vm) closeUpvalues(last int) {
+	for vm.openUpvalues != nil && vm.openUpvalues.slot >= last {
+		upvalue := vm.openUpvalues
+		upvalue.closed = vm.stack[upvalue.slot]
+		upvalue.isClosed = true
+		vm.openUpvalues = upvalue.next
+	}
+}
+
+func (vm *vm) runtimeError(format string, a ...interface{}) *vmRuntimeError {
+	err := &vmRuntimeError{message: fmt.Sprintf(format, a...)}
+	for i := len(vm.frames) - 1; i >= 0; i-- {
+		frame := vm.frames[i]
+		function := frame.closure.function
+		// the instruction that failed is the one before ip
+		line := function.chunk.lines[frame.ip-1]
+		if function.name == "" {
+			err.trace = append(err.trace, fmt.Sprintf("[line %d] in script", line))
+		} else {
+			err.trace = append(err.trace, fmt.Sprintf("[line %d] in %s()", line, function.name))
+		}
+	}
+	return err
+}
+
+type vmRuntimeError struct {
+	message string
+	// trace lists the call frames active when the error happened, innermost first.
+	trace []string
+}
+
+func (e *vmRuntimeError) Error() string {
+	return fmt.Sprintf("%s\n%s", e.message, strings.Join(e.trace, "\n"))
+}
@@ -0,0 +1,321 @@
+package lox
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runVM compiles source and runs it on a fresh virtual machine, returning what it printed followed by the
+// runtime error if any.
+func runVM(t *testing.T, source string, options ...VMOption) string {
+	t.Helper()
+	statements, _ := resolve(t, source)
+	compiler := NewCompiler()
+	function := compiler.Compile(statements)
+	require.False(t, compiler.HadErrors())
+	var output bytes.Buffer
+	options = append(options, WithVMOutput(&output), WithVMErrorOutput(&output))
+	_ = NewVM(options...).Interpret(function)
+	return output.String()
+}
+
+// TestVMMatchesInterpreter runs programs on both backends and expects the same output.
+func TestVMMatchesInterpreter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "arithmetic",
+			source:   "print 1 + 2 * 3 - 4 / 2; print -(1.5); print 1 / 3 < 1; print 2 >= 2; print 3 <= 2;",
+			expected: "5\n-1.5\ntrue\ntrue\nfalse\n",
+		},
+		{
+			name:     "strings and equality",
+			source:   `var a = "con" + "cat"; print a; print a == "concat"; print nil == false; print !nil; print 1 != 1;`,
+			expected: "concat\ntrue\nfalse\ntrue\nfalse\n",
+		},
+		{
+			name:     "logical operators",
+			source:   `print "hi" or 2; print nil or "yes"; print nil and 1; print 1 and 2;`,
+			expected: "hi\nyes\nnil\n2\n",
+		},
+		{
+			name: "scopes and control flow",
+			source: `
+var a = "global";
+{
+  var a = "outer";
+  {
+    var a = "inner";
+    print a;
+  }
+  print a;
+}
+print a;
+var sum = 0;
+for (var i = 0; i < 5; i = i + 1) {
+  if (i == 2) print "two"; else sum = sum + i;
+}
+print sum;
+var n = 0;
+while (n < 3) n = n + 1;
+print n;`,
+			expected: "inner\nouter\nglobal\ntwo\n8\n3\n",
+		},
+		{
+			name: "break and continue",
+			source: `
+for (var i = 0; i < 10; i = i + 1) {
+  var skipped = i * 2;
+  if (i == 1) continue;
+  if (i == 4) break;
+  print i;
+}
+var n = 0;
+while (true) {
+  n = n + 1;
+  {
+    var inner = n;
+    if (inner < 3) continue;
+  }
+  if (n >= 5) break;
+}
+print n;
+for (var i = 0; i < 3; i = i + 1) {
+  for (var j = 0; j < 3; j = j + 1) {
+    if (j == 1) continue;
+    if (j == 2) break;
+    print i * 10 + j;
+  }
+}
+for (var i = 0; i < 3; i = i + 1) {
+  var captured = i;
+  fun get() { return captured; }
+  if (i == 1) continue;
+  print get();
+}
+fun firstOver(limit) {
+  var i = 0;
+  while (true) {
+    if (i > limit) return i;
+    i = i + 1;
+  }
+}
+print firstOver(7);`,
+			expected: "0\n2\n3\n5\n0\n10\n20\n0\n2\n8\n",
+		},
+		{
+			name: "functions",
+			source: `
+fun fib(n) {
+  if (n <= 1) return n;
+  return fib(n - 2) + fib(n - 1);
+}
+print fib(15);
+fun noReturn() {}
+print noReturn();
+print fib;
+print clock;`,
+			expected: "610\nnil\n<fn fib>\n<native fn>\n",
+		},
+		{
+			name: "deep recursion",
+			source: `
+fun sum(n) {
+  if (n == 0) return 0;
+  return n + sum(n - 1);
+}
+print sum(100);
+print sum(5000) == 12502500;`,
+			expected: "5050\ntrue\n",
+		},
+		{
+			name: "closures",
+			source: `
+fun makeCounter() {
+  var i = 0;
+  fun count() {
+    i = i + 1;
+    return i;
+  }
+  return count;
+}
+var counter = makeCounter();
+counter();
+print counter();
+var first;
+var second;
+{
+  var shared = "before";
+  fun get() {
+    return shared;
+  }
+  fun set(value) {
+    shared = value;
+  }
+  first = get;
+  second = set;
+}
+second("after");
+print first();`,
+			expected: "2\nafter\n",
+		},
+		{
+			name: "classes",
+			source: `
+class Counter {
+  init(start) {
+    this.count = start;
+  }
+  increment() {
+    this.count = this.count + 1;
+    return this;
+  }
+}
+var counter = Counter(41);
+print counter.increment().count;
+var method = counter.increment;
+method();
+print counter.count;
+print Counter;
+print counter;`,
+			expected: "42\n43\nCounter\nCounter instance\n",
+		},
+		{
+			name: "fields shadow methods",
+			source: `
+class A {
+  f() {
+    return "method";
+  }
+}
+fun g() {
+  return "field";
+}
+var a = A();
+print a.f();
+a.f = g;
+print a.f();`,
+			expected: "method\nfield\n",
+		},
+		{
+			name: "inheritance",
+			source: `
+class A {
+  init(name) {
+    this.name = name;
+  }
+  method() {
+    return "A " + this.name;
+  }
+}
+class B < A {
+  init(name) {
+    super.init(name + "!");
+  }
+  method() {
+    var parent = super.method;
+    return "B " + parent();
+  }
+}
+print B("b").method();`,
+			expected: "B A b!\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, interpret(t, tc.source), "interpreter")
+			assert.Equal(t, tc.expected, runVM(t, tc.source), "vm")
+			assert.Equal(t, tc.expected, runVM(t, tc.source, WithStressGC()), "vm with stress GC")
+		})
+	}
+}
+
+func TestVMRuntimeErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "undefined variable",
+			source:   "print undefined;",
+			expected: "Undefined variable 'undefined'.\n[line 1] in script\n",
+		},
+		{
+			name:     "stack trace",
+			source:   "fun a() {\n  b();\n}\nfun b() {\n  return 1 + \"one\";\n}\na();",
+			expected: "Operands must be two numbers or two strings.\n[line 5] in b()\n[line 2] in a()\n[line 7] in script\n",
+		},
+		{
+			name:     "division by zero",
+			source:   "print 1 / 0;",
+			expected: "Right operand must not be 0.\n[line 1] in script\n",
+		},
+		{
+			name:     "arity",
+			source:   "fun f(a) {}\nf();",
+			expected: "Expected 1 arguments but got 0.\n[line 2] in script\n",
+		},
+		{
+			name:     "stack overflow",
+			source:   "fun f() {\n  f();\n}\nf();",
+			expected: "Stack overflow.\n",
+		},
+		{
+			name:     "inherit from a non-class",
+			source:   "var A = 1;\nclass B < A {}",
+			expected: "Superclass must be a class.\n[line 2] in script\n",
+		},
+	}
+
+	t.Run("max call depth", func(t *testing.T) {
+		source := "fun f(n) {\n  if (n > 0) f(n - 1);\n}\nf(3);"
+		assert.Equal(t, "", runVM(t, source, WithVMMaxCallDepth(4)))
+		assert.Contains(t, runVM(t, source, WithVMMaxCallDepth(3)), "Stack overflow.\n[line 2] in f()\n")
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := runVM(t, tc.source)
+			if tc.name == "stack overflow" {
+				assert.Contains(t, output, tc.expected)
+				return
+			}
+			assert.Equal(t, tc.expected, output)
+		})
+	}
+}
+
+const benchmarkSource = `
+fun fib(n) {
+  if (n < 2) return n;
+  return fib(n - 2) + fib(n - 1);
+}
+var total = 0;
+for (var i = 0; i < 20; i = i + 1) {
+  total = total + fib(i);
+}`
+
+func BenchmarkInterpreter(b *testing.B) {
+	statements := NewParser(NewScanner(benchmarkSource).ScanTokens()).Parse()
+	for n := 0; n < b.N; n++ {
+		interpreter := NewInterpreter()
+		NewResolver(interpreter).Resolve(statements)
+		interpreter.Interpret(statements)
+	}
+}
+
+func BenchmarkVM(b *testing.B) {
+	statements := NewParser(NewScanner(benchmarkSource).ScanTokens()).Parse()
+	function := NewCompiler().Compile(statements)
+	for n := 0; n < b.N; n++ {
+		NewVM().Interpret(function)
+	}
+}
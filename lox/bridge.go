@@ -0,0 +1,471 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	core "github.com/nockty/glox/internal/lox"
+)
+
+// ValueOf converts a Go value to a value of a script:
+//
+//   - nil, nil pointers and nil interfaces become nil;
+//   - booleans become booleans, strings become strings and all integer and floating-point types become
+//     numbers;
+//   - structs and pointers to structs become instances whose exported fields scripts can get and set. A
+//     field is named after its `lox` tag if it has one, and the fields tagged "-" are hidden. Scripts modify
+//     the struct a pointer points to, but a copy of a struct passed by value;
+//   - slices and arrays become lists, and maps whose keys are booleans, numbers or strings become maps.
+//     Their elements are copied, so the changes a script makes to them are not seen from Go;
+//   - a Value is returned as is.
+//
+// Other types, such as channels and functions, cannot be converted.
+func ValueOf(v interface{}) (Value, error) {
+	if value, ok := v.(Value); ok {
+		return value, nil
+	}
+	if v == nil {
+		return Nil(), nil
+	}
+	value, err := toLox(reflect.ValueOf(v), "", make(map[visit]bool))
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{value: value}, nil
+}
+
+// Decode converts the value to a Go value stored in the variable target points to. It is the reverse of
+// ValueOf: numbers can be decoded to any numeric type that holds them exactly, lists to slices and arrays,
+// maps to maps, and instances to structs, field by field. The instances converted from Go structs can also
+// be decoded to their original type, in which case a pointer points to the original struct.
+//
+// Decoding to an empty interface stores nil, a bool, a float64, a string, a []interface{}, a
+// map[interface{}]interface{}, a map[string]interface{} with the fields of an instance, or the pointer to
+// the Go struct of an instance converted from Go. Decoding to a Value stores the value itself.
+func (v Value) Decode(target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot decode to %T: target must be a non-nil pointer", target)
+	}
+	return fromLox(v.value, rv.Elem(), "")
+}
+
+// ConversionError is an error converting a value between Go and a script.
+type ConversionError struct {
+	// Path locates the value that could not be converted inside the converted value, such as
+	// "Items[2].Price". It is empty when the converted value itself is the problem.
+	Path    string
+	Message string
+}
+
+func (e *ConversionError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+func conversionError(path, format string, args ...interface{}) error {
+	return &ConversionError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+var valueType = reflect.TypeOf(Value{})
+
+// visit identifies a slice, a map or a pointer being converted.
+type visit struct {
+	typ     reflect.Type
+	pointer uintptr
+	length  int
+}
+
+// toLox converts a Go value to a lox value. seen holds the slices, maps and pointers being converted along
+// path, so that a value containing itself results in an error rather than an endless recursion.
+func toLox(rv reflect.Value, path string, seen map[visit]bool) (interface{}, error) {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Ptr:
+		if !rv.IsNil() && (rv.Kind() != reflect.Slice || rv.Len() > 0) {
+			key := visit{typ: rv.Type(), pointer: rv.Pointer()}
+			if rv.Kind() == reflect.Slice {
+				// slices of the same array differ by their length
+				key.length = rv.Len()
+			}
+			if seen[key] {
+				return nil, conversionError(path, "cannot convert %s: the value contains itself", rv.Type())
+			}
+			seen[key] = true
+			defer delete(seen, key)
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return toLox(rv.Elem(), path, seen)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Elem().Kind() == reflect.Struct {
+			return structObject{pointer: rv.Interface()}, nil
+		}
+		return toLox(rv.Elem(), path, seen)
+	case reflect.Struct:
+		if rv.Type() == valueType {
+			return rv.Interface().(Value).value, nil
+		}
+		if rv.CanAddr() {
+			return structObject{pointer: rv.Addr().Interface()}, nil
+		}
+		pointer := reflect.New(rv.Type())
+		pointer.Elem().Set(rv)
+		return structObject{pointer: pointer.Interface()}, nil
+	case reflect.Slice, reflect.Array:
+		elements := make([]interface{}, 0, rv.Len())
+		for idx := 0; idx < rv.Len(); idx++ {
+			element, err := toLox(rv.Index(idx), fmt.Sprintf("%s[%d]", path, idx), seen)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+		}
+		return core.NewList(elements), nil
+	case reflect.Map:
+		return mapToLox(rv, path, seen)
+	}
+	return nil, conversionError(path, "cannot convert %s to a lox value", rv.Type())
+}
+
+func mapToLox(rv reflect.Value, path string, seen map[visit]bool) (interface{}, error) {
+	keys := make([]interface{}, 0, rv.Len())
+	values := make([]interface{}, 0, rv.Len())
+	for _, key := range rv.MapKeys() {
+		keyPath := fmt.Sprintf("%s[%v]", path, key)
+		converted, err := toLox(key, keyPath, seen)
+		if err != nil {
+			return nil, err
+		}
+		switch core.KindOf(converted) {
+		case core.KindNil, core.KindBool, core.KindNumber, core.KindString:
+		default:
+			return nil, conversionError(keyPath, "cannot use %s as a map key", rv.Type().Key())
+		}
+		value, err := toLox(rv.MapIndex(key), keyPath, seen)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, converted)
+		values = append(values, value)
+	}
+	// Go maps have no order: sort the keys so that scripts behave the same from one run to the next
+	sort.Sort(byKey{keys: keys, values: values})
+	return core.NewMap(keys, values), nil
+}
+
+// byKey sorts map entries by key: nil first, then booleans, numbers and strings.
+type byKey struct {
+	keys   []interface{}
+	values []interface{}
+}
+
+func (b byKey) Len() int {
+	return len(b.keys)
+}
+
+func (b byKey) Less(i, j int) bool {
+	ki, kj := core.KindOf(b.keys[i]), core.KindOf(b.keys[j])
+	if ki != kj {
+		return ki < kj
+	}
+	switch key := b.keys[i].(type) {
+	case bool:
+		return !key && b.keys[j].(bool)
+	case float64:
+		return key < b.keys[j].(float64)
+	case string:
+		return key < b.keys[j].(string)
+	}
+	return false
+}
+
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
+
+func fromLox(value interface{}, rv reflect.Value, path string) error {
+	if rv.Type() == valueType {
+		rv.Set(reflect.ValueOf(Value{value: value}))
+		return nil
+	}
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		natural, err := toGo(value, path)
+		if err != nil {
+			return err
+		}
+		if natural == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+		} else {
+			rv.Set(reflect.ValueOf(natural))
+		}
+		return nil
+	}
+	if value == nil {
+		switch rv.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		return conversionError(path, "cannot convert nil to %s", rv.Type())
+	}
+	if object, ok := value.(structObject); ok {
+		pointer := reflect.ValueOf(object.pointer)
+		if pointer.Type().AssignableTo(rv.Type()) {
+			rv.Set(pointer)
+			return nil
+		}
+		if pointer.Elem().Type().AssignableTo(rv.Type()) {
+			rv.Set(pointer.Elem())
+			return nil
+		}
+	}
+	if rv.Kind() == reflect.Ptr {
+		element := reflect.New(rv.Type().Elem())
+		if err := fromLox(value, element.Elem(), path); err != nil {
+			return err
+		}
+		rv.Set(element)
+		return nil
+	}
+
+	switch value := value.(type) {
+	case bool:
+		if rv.Kind() == reflect.Bool {
+			rv.SetBool(value)
+			return nil
+		}
+	case float64:
+		return numberFromLox(value, rv, path)
+	case string:
+		if rv.Kind() == reflect.String {
+			rv.SetString(value)
+			return nil
+		}
+	}
+	if elements, ok := core.ListElements(value); ok {
+		return listFromLox(elements, rv, path)
+	}
+	if keys, values, ok := core.MapEntries(value); ok && rv.Kind() == reflect.Map {
+		return mapFromLox(keys, values, rv, path)
+	}
+	if fields, ok := core.InstanceFields(value); ok && rv.Kind() == reflect.Struct {
+		return structFromLox(fields, rv, path)
+	}
+	return conversionError(path, "cannot convert %s to %s", core.KindOf(value), rv.Type())
+}
+
+func numberFromLox(n float64, rv reflect.Value, path string) error {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 || rv.OverflowInt(int64(n)) {
+			return conversionError(path, "cannot convert %v to %s: out of range or not an integer", n, rv.Type())
+		}
+		rv.SetInt(int64(n))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n != math.Trunc(n) || n < 0 || n >= math.MaxUint64 || rv.OverflowUint(uint64(n)) {
+			return conversionError(path, "cannot convert %v to %s: out of range or not an integer", n, rv.Type())
+		}
+		rv.SetUint(uint64(n))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if rv.OverflowFloat(n) {
+			return conversionError(path, "cannot convert %v to %s: out of range", n, rv.Type())
+		}
+		rv.SetFloat(n)
+		return nil
+	}
+	return conversionError(path, "cannot convert number to %s", rv.Type())
+}
+
+func listFromLox(elements []interface{}, rv reflect.Value, path string) error {
+	switch rv.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(rv.Type(), len(elements), len(elements))
+		for idx, element := range elements {
+			if err := fromLox(element, slice.Index(idx), fmt.Sprintf("%s[%d]", path, idx)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+	case reflect.Array:
+		if len(elements) != rv.Len() {
+			return conversionError(path, "cannot convert list of %d elements to %s", len(elements), rv.Type())
+		}
+		for idx, element := range elements {
+			if err := fromLox(element, rv.Index(idx), fmt.Sprintf("%s[%d]", path, idx)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return conversionError(path, "cannot convert list to %s", rv.Type())
+}
+
+func mapFromLox(keys, values []interface{}, rv reflect.Value, path string) error {
+	m := reflect.MakeMapWithSize(rv.Type(), len(keys))
+	for idx, key := range keys {
+		keyPath := fmt.Sprintf("%s[%s]", path, core.Stringify(key))
+		goKey := reflect.New(rv.Type().Key()).Elem()
+		if err := fromLox(key, goKey, keyPath); err != nil {
+			return err
+		}
+		goValue := reflect.New(rv.Type().Elem()).Elem()
+		if err := fromLox(values[idx], goValue, keyPath); err != nil {
+			return err
+		}
+		m.SetMapIndex(goKey, goValue)
+	}
+	rv.Set(m)
+	return nil
+}
+
+func structFromLox(fields map[string]interface{}, rv reflect.Value, path string) error {
+	for name, value := range fields {
+		field, ok := fieldByName(rv, name)
+		if !ok {
+			// like encoding/json, fields the struct doesn't have are ignored
+			continue
+		}
+		if err := fromLox(value, field, joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toGo returns the natural Go representation of a value, as documented by Decode.
+func toGo(value interface{}, path string) (interface{}, error) {
+	switch value := value.(type) {
+	case nil, bool, float64, string:
+		return value, nil
+	case structObject:
+		return value.pointer, nil
+	}
+	if elements, ok := core.ListElements(value); ok {
+		list := make([]interface{}, 0, len(elements))
+		for idx, element := range elements {
+			converted, err := toGo(element, fmt.Sprintf("%s[%d]", path, idx))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	}
+	if keys, values, ok := core.MapEntries(value); ok {
+		m := make(map[interface{}]interface{}, len(keys))
+		for idx, key := range keys {
+			converted, err := toGo(values[idx], fmt.Sprintf("%s[%s]", path, core.Stringify(key)))
+			if err != nil {
+				return nil, err
+			}
+			m[key] = converted
+		}
+		return m, nil
+	}
+	if fields, ok := core.InstanceFields(value); ok {
+		m := make(map[string]interface{}, len(fields))
+		for name, field := range fields {
+			converted, err := toGo(field, joinPath(path, name))
+			if err != nil {
+				return nil, err
+			}
+			m[name] = converted
+		}
+		return m, nil
+	}
+	return nil, conversionError(path, "cannot convert %s to a Go value", core.KindOf(value))
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// structObject exposes a Go struct to scripts. It holds a pointer so that scripts can set the fields, and so
+// that two objects exposing the same struct are equal.
+type structObject struct {
+	pointer interface{}
+}
+
+// structObject implements HostObject
+var _ core.HostObject = structObject{}
+
+func (o structObject) Get(name string) (interface{}, error) {
+	field, ok := fieldByName(reflect.ValueOf(o.pointer).Elem(), name)
+	if !ok {
+		return nil, fmt.Errorf("Undefined property '%s'.", name)
+	}
+	value, err := toLox(field, "", make(map[visit]bool))
+	if err != nil {
+		return nil, fmt.Errorf("Can't get property '%s': %s.", name, err)
+	}
+	return value, nil
+}
+
+func (o structObject) Set(name string, value interface{}) error {
+	field, ok := fieldByName(reflect.ValueOf(o.pointer).Elem(), name)
+	if !ok {
+		return fmt.Errorf("Undefined property '%s'.", name)
+	}
+	// decode to a copy, so that a failed conversion leaves the field as it was
+	converted := reflect.New(field.Type()).Elem()
+	if err := fromLox(value, converted, ""); err != nil {
+		return fmt.Errorf("Can't set property '%s': %s.", name, err)
+	}
+	field.Set(converted)
+	return nil
+}
+
+func (o structObject) String() string {
+	return fmt.Sprintf("%s instance", reflect.TypeOf(o.pointer).Elem().Name())
+}
+
+// fieldByName returns the exported field of a struct that scripts know by name.
+func fieldByName(rv reflect.Value, name string) (reflect.Value, bool) {
+	t := rv.Type()
+	for idx := 0; idx < t.NumField(); idx++ {
+		field := t.Field(idx)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldName := field.Name
+		if tag, ok := field.Tag.Lookup("lox"); ok {
+			if tag == "-" {
+				continue
+			}
+			fieldName = strings.Split(tag, ",")[0]
+		}
+		if fieldName == name {
+			return rv.Field(idx), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,252 @@
+package lox
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City string
+	Zip  int `lox:"zip"`
+}
+
+type user struct {
+	Name    string
+	Age     uint8
+	Tags    []string
+	Scores  map[string]float64
+	Address address
+	Secret  string `lox:"-"`
+	hidden  int
+}
+
+func TestValueOf(t *testing.T) {
+	shared := []int{1}
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected string
+		kind     Kind
+	}{
+		{"nil", nil, "nil", KindNil},
+		{"bool", true, "true", KindBool},
+		{"int", 42, "42", KindNumber},
+		{"uint8", uint8(7), "7", KindNumber},
+		{"float32", float32(1.5), "1.5", KindNumber},
+		{"string", "a", "a", KindString},
+		{"nil pointer", (*user)(nil), "nil", KindNil},
+		{"pointer to number", new(int), "0", KindNumber},
+		{"slice", []interface{}{1, "a", nil, []int{2}}, `[1, "a", nil, [2]]`, KindList},
+		{"array", [2]bool{true, false}, "[true, false]", KindList},
+		{"shared slice", [][]int{shared, shared}, "[[1], [1]]", KindList},
+		{"map", map[string]int{"b": 2, "a": 1}, `{"a": 1, "b": 2}`, KindMap},
+		{"map with mixed keys", map[interface{}]int{"a": 1, 2.5: 2, false: 3, nil: 4}, `{nil: 4, false: 3, 2.5: 2, "a": 1}`, KindMap},
+		{"struct", address{City: "Paris"}, "address instance", KindInstance},
+		{"value", Number(3), "3", KindNumber},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := ValueOf(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value.String())
+			assert.Equal(t, tc.kind, value.Kind())
+		})
+	}
+}
+
+func TestValueOfErrors(t *testing.T) {
+	recursiveSlice := []interface{}{1, nil}
+	recursiveSlice[1] = recursiveSlice
+	recursiveMap := map[string]interface{}{"a": 1}
+	recursiveMap["b"] = []interface{}{recursiveMap}
+	recursivePointer := new(interface{})
+	*recursivePointer = recursivePointer
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"channel", make(chan int), "cannot convert chan int to a lox value"},
+		{"function", func() {}, "cannot convert func() to a lox value"},
+		{"nested", map[string][]interface{}{"a": {1, make(chan int)}}, "[a][1]: cannot convert chan int to a lox value"},
+		{"struct key", map[address]int{{}: 1}, "[{ 0}]: cannot use lox.address as a map key"},
+		{"recursive slice", recursiveSlice, "[1]: cannot convert []interface {}: the value contains itself"},
+		{"recursive map", recursiveMap, "[b][0]: cannot convert map[string]interface {}: the value contains itself"},
+		{"recursive pointer", recursivePointer, "cannot convert *interface {}: the value contains itself"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ValueOf(tc.value)
+			var conversionErr *ConversionError
+			require.True(t, errors.As(err, &conversionErr))
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestStructFields(t *testing.T) {
+	var stdout bytes.Buffer
+	interpreter := New(WithStdout(&stdout))
+	u := &user{
+		Name:    "Ada",
+		Age:     36,
+		Tags:    []string{"math"},
+		Scores:  map[string]float64{"chess": 3},
+		Address: address{City: "London", Zip: 1},
+		Secret:  "s",
+	}
+	require.NoError(t, interpreter.SetGlobal("user", u))
+	program, err := Compile("test.lox", `
+print user;
+print user.Name + " " + user.Address.City;
+print user.Tags;
+print user.Scores;
+user.Age = user.Age + 1;
+user.Address.zip = 2;
+var address = user.Address;
+address.City = "Paris";
+print user.Address == address;`)
+	require.NoError(t, err)
+	require.NoError(t, interpreter.Run(program))
+	assert.Equal(t, "user instance\nAda London\n[\"math\"]\n{\"chess\": 3}\ntrue\n", stdout.String())
+	// scripts modify the struct the pointer points to
+	assert.Equal(t, uint8(37), u.Age)
+	assert.Equal(t, address{City: "Paris", Zip: 2}, u.Address)
+
+	testCases := []struct {
+		source   string
+		expected string
+	}{
+		{"user.Secret;", "Undefined property 'Secret'."},
+		{"user.hidden;", "Undefined property 'hidden'."},
+		{"user.Address.Zip;", "Undefined property 'Zip'."},
+		{`user.Age = "old";`, "Can't set property 'Age': cannot convert string to uint8."},
+		{"user.Age = 256;", "Can't set property 'Age': cannot convert 256 to uint8: out of range or not an integer."},
+		{"user.Age = 1.5;", "Can't set property 'Age': cannot convert 1.5 to uint8: out of range or not an integer."},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			program, err := Compile("test.lox", tc.source)
+			require.NoError(t, err)
+			err = interpreter.Run(program)
+			var runtimeErr *RuntimeError
+			require.True(t, errors.As(err, &runtimeErr))
+			assert.Equal(t, tc.expected, runtimeErr.Message)
+		})
+	}
+	// a failed assignment leaves the field as it was
+	assert.Equal(t, uint8(37), u.Age)
+
+	// structs passed by value are copied
+	a := address{City: "Rome"}
+	require.NoError(t, interpreter.SetGlobal("address", a))
+	value, err := interpreter.Eval(`address.City = "Oslo"`)
+	require.NoError(t, err)
+	assert.Equal(t, String("Oslo"), value)
+	assert.Equal(t, "Rome", a.City)
+}
+
+func TestDecode(t *testing.T) {
+	interpreter := New()
+	program, err := Compile("test.lox", `
+class Address {}
+class User {
+  init(name, age) {
+    this.Name = name;
+    this.Age = age;
+    this.Address = Address();
+    this.Address.City = "Paris";
+    this.Address.zip = 75000;
+    this.Unknown = true;
+  }
+}
+var ada = User("Ada", 36);`)
+	require.NoError(t, err)
+	require.NoError(t, interpreter.Run(program))
+	require.NoError(t, interpreter.SetGlobal("list", []interface{}{1, "a", []int{2}}))
+	require.NoError(t, interpreter.SetGlobal("scores", map[string]int{"a": 1, "b": 2}))
+	original := &address{City: "Oslo"}
+	require.NoError(t, interpreter.SetGlobal("address", original))
+
+	value, err := interpreter.Eval("ada")
+	require.NoError(t, err)
+	var u user
+	require.NoError(t, value.Decode(&u))
+	assert.Equal(t, user{Name: "Ada", Age: 36, Address: address{City: "Paris", Zip: 75000}}, u)
+
+	var fields interface{}
+	require.NoError(t, value.Decode(&fields))
+	assert.Equal(t, map[string]interface{}{
+		"Name": "Ada", "Age": 36.0, "Unknown": true, "Address": map[string]interface{}{"City": "Paris", "zip": 75000.0},
+	}, fields)
+
+	value, err = interpreter.Eval("list")
+	require.NoError(t, err)
+	var list []interface{}
+	require.NoError(t, value.Decode(&list))
+	assert.Equal(t, []interface{}{1.0, "a", []interface{}{2.0}}, list)
+
+	value, err = interpreter.Eval("scores")
+	require.NoError(t, err)
+	var scores map[string]int8
+	require.NoError(t, value.Decode(&scores))
+	assert.Equal(t, map[string]int8{"a": 1, "b": 2}, scores)
+
+	// structs converted from Go decode to the original struct
+	value, err = interpreter.Eval("address")
+	require.NoError(t, err)
+	var pointer *address
+	require.NoError(t, value.Decode(&pointer))
+	assert.Same(t, original, pointer)
+	var copied address
+	require.NoError(t, value.Decode(&copied))
+	assert.Equal(t, *original, copied)
+
+	var number *float64
+	require.NoError(t, Number(2).Decode(&number))
+	assert.Equal(t, 2.0, *number)
+	var v Value
+	require.NoError(t, String("a").Decode(&v))
+	assert.Equal(t, String("a"), v)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	interpreter := New()
+	require.NoError(t, interpreter.SetGlobal("users", []user{{Tags: []string{"a"}}}))
+	clock, err := interpreter.Eval("clock")
+	require.NoError(t, err)
+	users, err := interpreter.Eval("users")
+	require.NoError(t, err)
+
+	var i int
+	var a [2]int
+	var b bool
+	var s []string
+	var natural interface{}
+	var m map[string]int
+	testCases := []struct {
+		name     string
+		value    Value
+		target   interface{}
+		expected string
+	}{
+		{"not a pointer", Number(1), i, "cannot decode to int: target must be a non-nil pointer"},
+		{"nil pointer", Number(1), (*int)(nil), "cannot decode to *int: target must be a non-nil pointer"},
+		{"wrong kind", String("a"), &i, "cannot convert string to int"},
+		{"nil", Nil(), &b, "cannot convert nil to bool"},
+		{"not an integer", Number(1.5), &i, "cannot convert 1.5 to int: out of range or not an integer"},
+		{"array length", users, &a, "cannot convert list of 1 elements to [2]int"},
+		{"nested", users, &s, "[0]: cannot convert instance to string"},
+		{"function", clock, &natural, "cannot convert function to a Go value"},
+		{"map", users, &m, "cannot convert list to map[string]int"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, tc.value.Decode(tc.target), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package lox
+
+import (
+	"strings"
+
+	core "github.com/nockty/glox/internal/lox"
+)
+
+// Position locates a range of source code.
+type Position struct {
+	// File is the name the source was compiled with, empty for expressions.
+	File string
+	// Line and Column locate the start of the range. They start at 1 and columns count bytes.
+	Line   int
+	Column int
+	// Offset is the byte offset of the start of the range and Length its number of bytes.
+	Offset int
+	Length int
+}
+
+func (p Position) String() string {
+	return core.Span(p).String()
+}
+
+// Error is a static error, found in a script before running it.
+type Error struct {
+	Position Position
+	Message  string
+	err      error
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// ErrorList is the list of static errors of a script.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	messages := make([]string, 0, len(l))
+	for _, err := range l {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// internal returns the errors of the interpreter the list comes from.
+func (l ErrorList) internal() core.ErrorList {
+	list := make(core.ErrorList, 0, len(l))
+	for _, err := range l {
+		list = append(list, err.err)
+	}
+	return list
+}
+
+// RuntimeError is an error raised while running a script.
+type RuntimeError struct {
+	Position Position
+	Message  string
+	err      error
+}
+
+func (e *RuntimeError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error of the interpreter, which wraps the cause of the error, if any.
+func (e *RuntimeError) Unwrap() error {
+	return e.err
+}
+
+// convertError converts an error of the interpreter to the errors of this package.
+func convertError(err error) error {
+	switch err := err.(type) {
+	case core.ErrorList:
+		list := make(ErrorList, 0, len(err))
+		for _, e := range err {
+			list = append(list, staticError(e))
+		}
+		return list
+	case *core.RuntimeError:
+		return &RuntimeError{Position: Position(err.Token.Span()), Message: err.Message, err: err}
+	}
+	return err
+}
+
+func staticError(err error) *Error {
+	switch e := err.(type) {
+	case *core.ScanError:
+		return &Error{Position: Position(e.Span), Message: e.Message, err: err}
+	case *core.ParseError:
+		return &Error{Position: Position(e.Span), Message: e.Message, err: err}
+	case *core.ResolveError:
+		return &Error{Position: Position(e.Span), Message: e.Message, err: err}
+	case *core.CompileError:
+		return &Error{Position: Position(e.Span), Message: e.Message, err: err}
+	}
+	return &Error{Message: err.Error(), err: err}
+}
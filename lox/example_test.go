@@ -0,0 +1,60 @@
+package lox_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/nockty/glox/lox"
+)
+
+func Example() {
+	interpreter := lox.New()
+	program, err := lox.Compile("prices.lox", `
+var total = 0;
+fun add(price, quantity) { total = total + price * quantity; }
+add(2.5, 4);
+add(1, 3);
+print "computed";`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := interpreter.Run(program); err != nil {
+		fmt.Println(err)
+		return
+	}
+	total, _ := interpreter.Eval("total")
+	fmt.Println(total)
+	// Output:
+	// computed
+	// 13
+}
+
+func ExampleInterpreter_Define() {
+	interpreter := lox.New()
+	interpreter.Define("max", lox.Variadic, func(args []lox.Value) (lox.Value, error) {
+		if len(args) == 0 {
+			return lox.Nil(), errors.New("max of no numbers")
+		}
+		result := math.Inf(-1)
+		for _, arg := range args {
+			n, err := arg.AsNumber()
+			if err != nil {
+				return lox.Nil(), err
+			}
+			result = math.Max(result, n)
+		}
+		return lox.Number(result), nil
+	})
+	result, _ := interpreter.Eval("max(3, 7, 5)")
+	fmt.Println(result)
+	_, err := interpreter.Eval("max()")
+	var runtimeErr *lox.RuntimeError
+	if errors.As(err, &runtimeErr) {
+		fmt.Printf("%s: %s\n", runtimeErr.Position, runtimeErr.Message)
+	}
+	// Output:
+	// 7
+	// line 1:5: max of no numbers
+}
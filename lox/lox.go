@@ -0,0 +1,221 @@
+// Package lox runs Lox scripts from Go programs.
+//
+// Scripts are compiled once, then run by interpreters, which keep their global state between runs:
+//
+//	program, err := lox.Compile("rules.lox", source)
+//	if err != nil {
+//		return err
+//	}
+//	interpreter := lox.New(lox.WithStdout(&output))
+//	if err := interpreter.Run(program); err != nil {
+//		return err
+//	}
+//	total, err := interpreter.Eval("total")
+//
+// Static errors, found before running a script, are returned as an ErrorList and errors raised while running
+// it as a *RuntimeError.
+package lox
+
+import (
+	"context"
+	"io"
+	"os"
+
+	core "github.com/nockty/glox/internal/lox"
+)
+
+// Option configures an interpreter.
+type Option func(*config)
+
+type config struct {
+	stdout io.Writer
+	stderr io.Writer
+	// limits holds the options limiting the resources of runs, passed as is to the interpreter.
+	limits []core.InterpreterOption
+}
+
+// WithStdout sets where print statements write. It defaults to os.Stdout.
+func WithStdout(w io.Writer) Option {
+	return func(c *config) {
+		c.stdout = w
+	}
+}
+
+// WithStderr sets where errors are reported, with an excerpt of the source for static errors. By default,
+// errors are only returned.
+func WithStderr(w io.Writer) Option {
+	return func(c *config) {
+		c.stderr = w
+	}
+}
+
+// WithStepLimit limits the number of statements a run can execute, so that scripts can't loop forever. A
+// run exceeding it stops with a *RuntimeError wrapping ErrStepLimit. By default, it is not limited.
+func WithStepLimit(steps int) Option {
+	return func(c *config) {
+		c.limits = append(c.limits, core.WithStepLimit(steps))
+	}
+}
+
+// WithMaxCallDepth limits the number of nested calls. A call exceeding it stops the run with a
+// *RuntimeError wrapping ErrStackOverflow. It defaults to 10000, and 0 removes the limit, in which case an
+// infinite recursion crashes the program.
+func WithMaxCallDepth(depth int) Option {
+	return func(c *config) {
+		c.limits = append(c.limits, core.WithMaxCallDepth(depth))
+	}
+}
+
+// WithMemoryLimit limits the number of bytes a run can allocate for strings, environments, instances and
+// collections. A run exceeding it stops with a *RuntimeError wrapping ErrMemoryLimit. The limit applies to
+// the total allocated by a run rather than to the memory in use, and sizes are estimates. By default, memory
+// is not limited.
+func WithMemoryLimit(bytes int) Option {
+	return func(c *config) {
+		c.limits = append(c.limits, core.WithMemoryLimit(bytes))
+	}
+}
+
+// Errors wrapped by the runtime errors of the runs exceeding their limits, to check with errors.Is. When the
+// context of a run is done, its runtime error wraps the error of the context instead, such as
+// context.DeadlineExceeded.
+var (
+	ErrStepLimit     = core.ErrStepLimit
+	ErrStackOverflow = core.ErrStackOverflow
+	ErrMemoryLimit   = core.ErrMemoryLimit
+)
+
+// Program is a script without static errors, ready to run.
+type Program struct {
+	name       string
+	source     string
+	statements []core.Stmt
+}
+
+// Compile parses source and checks it for static errors, which are returned as an ErrorList. name appears
+// in the positions of errors: it is typically the name of the file source comes from.
+func Compile(name, source string) (*Program, error) {
+	statements, err := core.Parse(name, source)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	resolver := core.NewResolver(core.NewInterpreter())
+	resolver.Resolve(statements)
+	if resolver.HadErrors() {
+		return nil, convertError(core.ErrorList(resolver.Errors()))
+	}
+	return &Program{name: name, source: source, statements: statements}, nil
+}
+
+// Name returns the name the program was compiled with.
+func (p *Program) Name() string {
+	return p.name
+}
+
+// Interpreter runs programs. Its global state persists from one run to the next. It is not safe for
+// concurrent use, but several interpreters can run the same program concurrently.
+type Interpreter struct {
+	interpreter *core.Interpreter
+	stderr      io.Writer
+}
+
+// New creates an interpreter.
+func New(options ...Option) *Interpreter {
+	c := config{stdout: os.Stdout}
+	for _, option := range options {
+		option(&c)
+	}
+	coreOptions := append([]core.InterpreterOption{core.WithOutput(c.stdout)}, c.limits...)
+	if c.stderr != nil {
+		coreOptions = append(coreOptions, core.WithErrorOutput(c.stderr))
+	}
+	return &Interpreter{
+		interpreter: core.NewInterpreter(coreOptions...),
+		stderr:      c.stderr,
+	}
+}
+
+// Function is a Go function callable from scripts. It returns either the result of the call or an error,
+// which becomes a runtime error located at the call.
+type Function func(args []Value) (Value, error)
+
+// Variadic is the arity of the functions that accept any number of arguments.
+const Variadic = core.Variadic
+
+// Define defines a global function implemented in Go, which scripts can call with arity arguments, or with
+// any number of arguments if arity is Variadic. It replaces any global of the same name.
+func (i *Interpreter) Define(name string, arity int, function Function) {
+	i.interpreter.Define(name, arity, func(arguments []interface{}) (interface{}, error) {
+		args := make([]Value, 0, len(arguments))
+		for _, argument := range arguments {
+			args = append(args, Value{value: argument})
+		}
+		result, err := function(args)
+		if err != nil {
+			return nil, err
+		}
+		return result.value, nil
+	})
+}
+
+// SetGlobal converts a Go value with ValueOf and defines it as a global variable of the scripts, replacing
+// any global of the same name.
+func (i *Interpreter) SetGlobal(name string, value interface{}) error {
+	converted, err := ValueOf(value)
+	if err != nil {
+		return err
+	}
+	i.interpreter.SetGlobal(name, converted.value)
+	return nil
+}
+
+// Run runs a program. A runtime error stops the program and is returned as a *RuntimeError.
+func (i *Interpreter) Run(program *Program) error {
+	return i.RunContext(context.Background(), program)
+}
+
+// RunContext is like Run, but stops the program when ctx is done, with a *RuntimeError wrapping the error of
+// ctx. The context is checked in loops and calls.
+func (i *Interpreter) RunContext(ctx context.Context, program *Program) error {
+	// the program was checked when compiled: resolving it again only binds its variables for this
+	// interpreter
+	core.NewResolver(i.interpreter).Resolve(program.statements)
+	if err := i.interpreter.InterpretContext(ctx, program.statements); err != nil {
+		return convertError(err)
+	}
+	return nil
+}
+
+// Eval evaluates source made of a single expression, such as "total * 2", and returns its value. The
+// expression can use the globals defined by the programs run before.
+func (i *Interpreter) Eval(expression string) (Value, error) {
+	return i.EvalContext(context.Background(), expression)
+}
+
+// EvalContext is like Eval, but stops the evaluation when ctx is done, with a *RuntimeError wrapping the
+// error of ctx.
+func (i *Interpreter) EvalContext(ctx context.Context, expression string) (Value, error) {
+	value, err := i.interpreter.EvalContext(ctx, "", expression)
+	if err != nil {
+		return Value{}, convertError(err)
+	}
+	return Value{value: value}, nil
+}
+
+// Run compiles source and runs it with a new interpreter.
+func Run(name, source string, options ...Option) error {
+	interpreter := New(options...)
+	program, err := Compile(name, source)
+	if err != nil {
+		if interpreter.stderr != nil {
+			core.Report(interpreter.stderr, err.(ErrorList).internal(), name, source)
+		}
+		return err
+	}
+	return interpreter.Run(program)
+}
+
+// Eval evaluates source made of a single expression with a new interpreter.
+func Eval(expression string, options ...Option) (Value, error) {
+	return New(options...).Eval(expression)
+}
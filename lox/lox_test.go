@@ -0,0 +1,208 @@
+package lox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := Run("test.lox", `
+fun greet(name) { return "Hello, " + name + "!"; }
+print greet("glox");`, WithStdout(&stdout), WithStderr(&stderr))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, glox!\n", stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestStaticErrors(t *testing.T) {
+	_, err := Compile("test.lox", "var a = 1;\nprint a +;\nreturn;")
+	var list ErrorList
+	require.True(t, errors.As(err, &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, Position{File: "test.lox", Line: 2, Column: 10, Offset: 20, Length: 1}, list[0].Position)
+	assert.Equal(t, "test.lox:2:10", list[0].Position.String())
+	assert.Equal(t, "Expect expression.", list[0].Message)
+	assert.Equal(t, "[test.lox:2:10] Error at ';': Expect expression.", err.Error())
+
+	_, err = Compile("test.lox", "return;")
+	require.True(t, errors.As(err, &list))
+	assert.Equal(t, "Can't return from top-level code.", list[0].Message)
+
+	// errors are reported on stderr when asked to
+	var stdout, stderr bytes.Buffer
+	err = Run("test.lox", "print 1;\nprint 1 +;", WithStdout(&stdout), WithStderr(&stderr))
+	require.True(t, errors.As(err, &list))
+	assert.Empty(t, stdout.String())
+	assert.Equal(t, `error: Expect expression.
+ --> test.lox:2:10
+  |
+2 | print 1 +;
+  |          ^
+`, stderr.String())
+}
+
+func TestRuntimeError(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := Run("test.lox", "print 1;\nprint -\"a\";\nprint 2;", WithStdout(&stdout), WithStderr(&stderr))
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.Equal(t, Position{File: "test.lox", Line: 2, Column: 7, Offset: 15, Length: 1}, runtimeErr.Position)
+	assert.Equal(t, "Operand must be a number.", runtimeErr.Message)
+	assert.Equal(t, "1\n", stdout.String())
+	assert.Equal(t, err.Error()+"\n", stderr.String())
+}
+
+func TestInterpreter(t *testing.T) {
+	var stdout bytes.Buffer
+	interpreter := New(WithStdout(&stdout))
+	program, err := Compile("counter.lox", `
+var count = 0;
+fun increment() { count = count + 1; print count; }`)
+	require.NoError(t, err)
+	assert.Equal(t, "counter.lox", program.Name())
+	require.NoError(t, interpreter.Run(program))
+
+	increment, err := Compile("increment.lox", "increment();")
+	require.NoError(t, err)
+	// globals persist between runs
+	require.NoError(t, interpreter.Run(increment))
+	require.NoError(t, interpreter.Run(increment))
+	assert.Equal(t, "1\n2\n", stdout.String())
+
+	value, err := interpreter.Eval("count * 10")
+	require.NoError(t, err)
+	assert.Equal(t, Number(20), value)
+
+	// programs are independent from interpreters
+	var other bytes.Buffer
+	require.NoError(t, New(WithStdout(&other)).Run(program))
+	assert.Empty(t, other.String())
+}
+
+func TestEval(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expected   Value
+	}{
+		{"1 + 2 * 3", Number(7)},
+		{`"a" + "b"`, String("ab")},
+		{"!nil", Bool(true)},
+		{"nil", Nil()},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			value, err := Eval(tc.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+
+	_, err := Eval("1 + 2; 3")
+	var list ErrorList
+	require.True(t, errors.As(err, &list))
+	assert.Equal(t, "Expect end of expression.", list[0].Message)
+
+	_, err = Eval("undefined")
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.Equal(t, "Undefined variable 'undefined'.", runtimeErr.Message)
+}
+
+func TestDefine(t *testing.T) {
+	var stdout bytes.Buffer
+	interpreter := New(WithStdout(&stdout))
+	interpreter.Define("double", 1, func(args []Value) (Value, error) {
+		n, err := args[0].AsNumber()
+		if err != nil {
+			return Nil(), err
+		}
+		return Number(2 * n), nil
+	})
+	interpreter.Define("sum", Variadic, func(args []Value) (Value, error) {
+		total := 0.0
+		for _, arg := range args {
+			n, err := arg.AsNumber()
+			if err != nil {
+				return Nil(), err
+			}
+			total += n
+		}
+		return Number(total), nil
+	})
+	program, err := Compile("test.lox", `
+print double(21);
+print sum();
+print sum(1, 2, 3);
+var f = double;
+print f(sum(1, 1));
+print double;`)
+	require.NoError(t, err)
+	require.NoError(t, interpreter.Run(program))
+	assert.Equal(t, "42\n0\n6\n4\n<native fn>\n", stdout.String())
+
+	// errors returned from Go are located at the call
+	program, err = Compile("test.lox", "print 1;\nprint double(\"a\");")
+	require.NoError(t, err)
+	err = interpreter.Run(program)
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.Equal(t, "expected number, got string", runtimeErr.Message)
+	assert.Equal(t, Position{File: "test.lox", Line: 2, Column: 17, Offset: 25, Length: 1}, runtimeErr.Position)
+
+	// and can be caught by scripts
+	stdout.Reset()
+	program, err = Compile("test.lox", "try {\n  double(\"a\");\n} catch (e) {\n  print e.message;\n}")
+	require.NoError(t, err)
+	require.NoError(t, interpreter.Run(program))
+	assert.Equal(t, "expected number, got string\n", stdout.String())
+
+	program, err = Compile("test.lox", "double(1, 2);")
+	require.NoError(t, err)
+	err = interpreter.Run(program)
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.Equal(t, "Expected 1 arguments but got 2.", runtimeErr.Message)
+}
+
+func TestLimits(t *testing.T) {
+	program, err := Compile("loop.lox", "while (true) {}")
+	require.NoError(t, err)
+
+	err = New(WithStepLimit(1000)).Run(program)
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.True(t, errors.Is(err, ErrStepLimit))
+	assert.Equal(t, "Step limit exceeded.", runtimeErr.Message)
+	assert.Equal(t, "loop.lox:1:1", runtimeErr.Position.String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = New().RunContext(ctx, program)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	_, err = New(WithMaxCallDepth(10)).Eval("clock() + clock()")
+	assert.NoError(t, err)
+	interpreter := New(WithMaxCallDepth(10))
+	recursive, err := Compile("recursive.lox", "fun f(n) { return f(n + 1); }")
+	require.NoError(t, err)
+	require.NoError(t, interpreter.Run(recursive))
+	_, err = interpreter.Eval("f(0)")
+	assert.True(t, errors.Is(err, ErrStackOverflow))
+}
+
+func TestMemoryLimit(t *testing.T) {
+	_, err := New(WithMemoryLimit(1024)).Eval(`"a" + "b"`)
+	require.NoError(t, err)
+
+	err = Run("test.lox", `var s = "abc"; while (true) s = s + s;`, WithMemoryLimit(1024*1024))
+	var runtimeErr *RuntimeError
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.True(t, errors.Is(err, ErrMemoryLimit))
+	assert.Equal(t, "Memory limit exceeded.", runtimeErr.Message)
+}
@@ -0,0 +1,105 @@
+package lox
+
+import (
+	"fmt"
+
+	core "github.com/nockty/glox/internal/lox"
+)
+
+// Kind is the type of a value.
+type Kind = core.Kind
+
+const (
+	KindNil      = core.KindNil
+	KindBool     = core.KindBool
+	KindNumber   = core.KindNumber
+	KindString   = core.KindString
+	KindFunction = core.KindFunction
+	KindClass    = core.KindClass
+	KindInstance = core.KindInstance
+	KindList     = core.KindList
+	KindMap      = core.KindMap
+	KindRange    = core.KindRange
+	KindObject   = core.KindObject
+)
+
+// Value is a value of a script. The zero value is nil.
+type Value struct {
+	value interface{}
+}
+
+// Nil returns the nil value.
+func Nil() Value {
+	return Value{}
+}
+
+// Bool returns a boolean value.
+func Bool(b bool) Value {
+	return Value{value: b}
+}
+
+// Number returns a number value.
+func Number(n float64) Value {
+	return Value{value: n}
+}
+
+// String returns a string value.
+func String(s string) Value {
+	return Value{value: s}
+}
+
+// Kind returns the type of the value.
+func (v Value) Kind() Kind {
+	return core.KindOf(v.value)
+}
+
+// IsNil reports whether the value is nil.
+func (v Value) IsNil() bool {
+	return v.value == nil
+}
+
+// AsBool returns the value of a boolean, or an error if the value is not a boolean.
+func (v Value) AsBool() (bool, error) {
+	b, ok := v.value.(bool)
+	if !ok {
+		return false, v.kindError(KindBool)
+	}
+	return b, nil
+}
+
+// AsNumber returns the value of a number, or an error if the value is not a number.
+func (v Value) AsNumber() (float64, error) {
+	n, ok := v.value.(float64)
+	if !ok {
+		return 0, v.kindError(KindNumber)
+	}
+	return n, nil
+}
+
+// AsString returns the value of a string, or an error if the value is not a string.
+func (v Value) AsString() (string, error) {
+	s, ok := v.value.(string)
+	if !ok {
+		return "", v.kindError(KindString)
+	}
+	return s, nil
+}
+
+// Truthy reports whether the value is true when used as a condition: only nil and false are not.
+func (v Value) Truthy() bool {
+	return core.IsTruthy(v.value)
+}
+
+// Equal reports whether two values are equal, as with the == operator.
+func (v Value) Equal(other Value) bool {
+	return core.IsEqual(v.value, other.value)
+}
+
+// String returns the representation of the value written by print statements.
+func (v Value) String() string {
+	return core.Stringify(v.value)
+}
+
+func (v Value) kindError(expected Kind) error {
+	return fmt.Errorf("expected %s, got %s", expected, v.Kind())
+}
@@ -0,0 +1,67 @@
+package lox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue(t *testing.T) {
+	assert.Equal(t, KindNil, Value{}.Kind())
+	assert.True(t, Nil().IsNil())
+	assert.False(t, Nil().Truthy())
+	assert.Equal(t, "nil", Nil().String())
+
+	b, err := Bool(true).AsBool()
+	require.NoError(t, err)
+	assert.True(t, b)
+	assert.Equal(t, KindBool, Bool(false).Kind())
+	assert.False(t, Bool(false).Truthy())
+
+	n, err := Number(1.5).AsNumber()
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, n)
+	assert.Equal(t, "1.5", Number(1.5).String())
+	assert.True(t, Number(0).Truthy())
+
+	s, err := String("lox").AsString()
+	require.NoError(t, err)
+	assert.Equal(t, "lox", s)
+	assert.Equal(t, KindString, String("").Kind())
+
+	_, err = String("1").AsNumber()
+	assert.EqualError(t, err, "expected number, got string")
+	_, err = Nil().AsBool()
+	assert.EqualError(t, err, "expected boolean, got nil")
+	_, err = Number(1).AsString()
+	assert.EqualError(t, err, "expected string, got number")
+
+	assert.True(t, Number(1).Equal(Number(1)))
+	assert.False(t, Number(1).Equal(String("1")))
+	assert.True(t, Nil().Equal(Value{}))
+}
+
+func TestObjectValues(t *testing.T) {
+	interpreter := New()
+	program, err := Compile("", "class Point { init(x) { this.x = x; } } fun f() {} var p = Point(1);")
+	require.NoError(t, err)
+	require.NoError(t, interpreter.Run(program))
+	testCases := []struct {
+		expression string
+		kind       Kind
+		str        string
+	}{
+		{"Point", KindClass, "Point"},
+		{"p", KindInstance, "Point instance"},
+		{"f", KindFunction, "<fn f>"},
+		{"clock", KindFunction, "<native fn>"},
+	}
+	for _, tc := range testCases {
+		value, err := interpreter.Eval(tc.expression)
+		require.NoError(t, err)
+		assert.Equal(t, tc.kind, value.Kind())
+		assert.Equal(t, tc.str, value.String())
+		assert.True(t, value.Truthy())
+	}
+}
@@ -31,9 +31,19 @@ func main() {
 	types := []string{
 		"Assign   : name Token, value Expr",
 		"Binary   : left Expr, operator Token, right Expr",
+		"Call     : callee Expr, paren Token, arguments []Expr",
+		"Get      : object Expr, name Token",
 		"Grouping : expression Expr",
+		"Index    : object Expr, bracket Token, index Expr",
+		"IndexSet : object Expr, bracket Token, index Expr, value Expr",
+		"List     : bracket Token, elements []Expr",
 		"Literal  : value interface{}",
 		"Logical  : left Expr, operator Token, right Expr",
+		"Map      : brace Token, keys []Expr, values []Expr",
+		"Set      : object Expr, name Token, value Expr",
+		"Slice    : object Expr, bracket Token, start Expr, end Expr",
+		"Super    : keyword Token, method Token",
+		"This     : keyword Token",
 		"Unary    : operator Token, right Expr",
 		"Variable : name Token",
 	}
@@ -43,11 +53,19 @@ func main() {
 	}
 	types = []string{
 		"Block      : statements []Stmt",
+		"Break      : keyword Token",
+		"Class      : name Token, superclass *VariableExpr, methods []*FunctionStmt",
+		"Continue   : keyword Token",
 		"Expression : expression Expr",
+		"ForIn      : keyword Token, name Token, in Token, iterable Expr, body Stmt",
+		"Function   : name Token, params []Token, body []Stmt",
 		"If         : condition Expr, thenBranch Stmt, elseBranch Stmt",
 		"Print      : expression Expr",
+		"Return     : keyword Token, value Expr",
+		"Throw      : keyword Token, value Expr",
+		"Try        : keyword Token, body []Stmt, catchName Token, catchBody []Stmt, finallyBody []Stmt",
 		"Var        : name Token, initializer Expr",
-		"While      : condition Expr, body Stmt",
+		"While      : keyword Token, condition Expr, body Stmt, increment Expr",
 	}
 	err = defineAST(outDir, "Stmt", types)
 	if err != nil {
@@ -117,6 +135,9 @@ func defineAST(outDir, baseName string, types []string) error {
 func defineBase(w *bufio.Writer, baseName string) error {
 	lines := []string{
 		fmt.Sprintf("type %s interface {", strings.Title(baseName)),
+		"// Span returns the location of the node in the source.",
+		"Span() Span",
+		"setSpan(span Span)",
 		fmt.Sprintf("Accept(v visitor%s) interface{}", strings.Title(baseName)),
 	}
 	// Accept by go type
@@ -160,7 +181,7 @@ func defineType(w *bufio.Writer, baseName, typeName, fieldList string) error {
 		fmt.Sprintf("type %s struct {", typeName),
 	}
 	lines = append(lines, fields...)
-	lines = append(lines, "}", "")
+	lines = append(lines, "span Span", "}", "")
 	// implements Expr
 	lines = append(lines,
 		fmt.Sprintf("// %s implements %s", typeName, strings.Title(baseName)),
@@ -177,6 +198,17 @@ func defineType(w *bufio.Writer, baseName, typeName, fieldList string) error {
 		lines = append(lines, fmt.Sprintf("%s: %s,", name, name))
 	}
 	lines = append(lines, "}", "}", "")
+	// span
+	lines = append(lines,
+		fmt.Sprintf("func (expr *%s) Span() Span {", typeName),
+		"return expr.span",
+		"}",
+		"",
+		fmt.Sprintf("func (expr *%s) setSpan(span Span) {", typeName),
+		"expr.span = span",
+		"}",
+		"",
+	)
 	// visitor pattern
 	lines = append(lines,
 		fmt.Sprintf("func (expr *%s) Accept(v visitor%s) interface{} {", typeName, strings.Title(baseName)),